@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidJWT est renvoyée pour tout token mal formé, mal signé ou expiré
+var ErrInvalidJWT = errors.New("invalid JWT")
+
+// Claims sont les informations contenues dans un token d'accès JWT
+type Claims struct {
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles"`
+	SessionID string   `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// JWTManager signe et vérifie les tokens d'accès JWT
+type JWTManager struct {
+	keys   *KeySet
+	issuer string
+}
+
+// NewJWTManager crée un JWTManager utilisant le jeu de clés donné
+func NewJWTManager(keys *KeySet, issuer string) *JWTManager {
+	return &JWTManager{keys: keys, issuer: issuer}
+}
+
+// Issue signe un token d'accès pour un utilisateur avec la clé active.
+// sessionID rattache le token à la session qui permet de le rafraîchir.
+func (m *JWTManager) Issue(user *models.User, sessionID string, expiresAt time.Time) (string, error) {
+	key := m.keys.activeKey()
+	now := time.Now()
+
+	claims := Claims{
+		Name:      user.Name,
+		Roles:     user.Roles,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	var token *jwt.Token
+	var signingKey any
+	switch key.Algorithm {
+	case AlgHS256:
+		token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signingKey = key.secret
+	case AlgEdDSA:
+		token = jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		signingKey = key.privateKey
+	default:
+		return "", fmt.Errorf("unsupported algorithm %q", key.Algorithm)
+	}
+
+	token.Header["kid"] = key.ID
+	return token.SignedString(signingKey)
+}
+
+// Verify vérifie la signature, l'émetteur et l'expiration d'un token
+func (m *JWTManager) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, m.verificationKey,
+		jwt.WithValidMethods([]string{AlgHS256, AlgEdDSA}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJWT, err)
+	}
+
+	return claims, nil
+}
+
+// verificationKey choisit la clé de vérification d'après le kid du token
+func (m *JWTManager) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, exists := m.keys.key(kid)
+	if !exists {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	// L'algorithme du token doit être celui de la clé, sinon un token HS256
+	// pourrait être signé avec une clé publique EdDSA
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("algorithm %q does not match key %q", token.Method.Alg(), kid)
+	}
+
+	if key.Algorithm == AlgHS256 {
+		return key.secret, nil
+	}
+	return key.publicKey, nil
+}
+
+// LooksLikeJWT distingue un JWT (trois segments) d'un token opaque
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -0,0 +1,161 @@
+// Package auth émet et vérifie les tokens d'accès JWT signés.
+// Contrairement aux tokens opaques, ils sont vérifiés sans consulter le
+// stockage et fonctionnent donc avec plusieurs instances du serveur.
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Algorithmes de signature supportés
+const (
+	AlgHS256 = "HS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// minHMACSecretLength est la taille minimale d'un secret HS256 (256 bits)
+const minHMACSecretLength = 32
+
+// Key est une clé de signature identifiée par son kid
+type Key struct {
+	ID         string
+	Algorithm  string
+	secret     []byte
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// canSign indique si la clé permet de signer (et pas seulement de vérifier)
+func (k *Key) canSign() bool {
+	switch k.Algorithm {
+	case AlgHS256:
+		return len(k.secret) > 0
+	case AlgEdDSA:
+		return k.privateKey != nil
+	}
+	return false
+}
+
+// KeySet regroupe les clés connues. La clé active signe les nouveaux tokens,
+// les autres ne servent qu'à vérifier les tokens émis avant une rotation.
+type KeySet struct {
+	active string
+	keys   map[string]*Key
+}
+
+// keySetFile est le format JSON du fichier de clés :
+//
+//	{
+//	  "active": "2026-10",
+//	  "keys": [
+//	    {"kid": "2026-10", "alg": "EdDSA", "private_key": "<seed ed25519 en base64>"},
+//	    {"kid": "2026-09", "alg": "EdDSA", "public_key": "<clé publique en base64>"},
+//	    {"kid": "legacy", "alg": "HS256", "secret": "<secret en base64>"}
+//	  ]
+//	}
+type keySetFile struct {
+	Active string `json:"active"`
+	Keys   []struct {
+		ID         string `json:"kid"`
+		Algorithm  string `json:"alg"`
+		Secret     string `json:"secret"`
+		PrivateKey string `json:"private_key"`
+		PublicKey  string `json:"public_key"`
+	} `json:"keys"`
+}
+
+// LoadKeySet lit un fichier de clés au format JSON
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing key set %s: %w", path, err)
+	}
+
+	set := &KeySet{active: file.Active, keys: make(map[string]*Key)}
+	for _, k := range file.Keys {
+		if k.ID == "" {
+			return nil, errors.New("key set: every key needs a kid")
+		}
+		if _, exists := set.keys[k.ID]; exists {
+			return nil, fmt.Errorf("key set: duplicate kid %q", k.ID)
+		}
+
+		key := &Key{ID: k.ID, Algorithm: k.Algorithm}
+		switch k.Algorithm {
+		case AlgHS256:
+			key.secret, err = decodeKey(k.Secret, 0)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: secret: %w", k.ID, err)
+			}
+			if len(key.secret) < minHMACSecretLength {
+				return nil, fmt.Errorf("key %q: HS256 secret must be at least %d bytes", k.ID, minHMACSecretLength)
+			}
+		case AlgEdDSA:
+			if k.PrivateKey != "" {
+				seed, err := decodeKey(k.PrivateKey, ed25519.SeedSize)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: private_key: %w", k.ID, err)
+				}
+				key.privateKey = ed25519.NewKeyFromSeed(seed)
+				key.publicKey = key.privateKey.Public().(ed25519.PublicKey)
+			} else {
+				public, err := decodeKey(k.PublicKey, ed25519.PublicKeySize)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: public_key: %w", k.ID, err)
+				}
+				key.publicKey = ed25519.PublicKey(public)
+			}
+		default:
+			return nil, fmt.Errorf("key %q: unsupported algorithm %q", k.ID, k.Algorithm)
+		}
+
+		set.keys[k.ID] = key
+	}
+
+	active, exists := set.keys[set.active]
+	if !exists {
+		return nil, fmt.Errorf("key set: active kid %q not found", set.active)
+	}
+	if !active.canSign() {
+		return nil, fmt.Errorf("key set: active key %q cannot sign (missing private key or secret)", set.active)
+	}
+
+	return set, nil
+}
+
+// activeKey retourne la clé utilisée pour signer
+func (s *KeySet) activeKey() *Key {
+	return s.keys[s.active]
+}
+
+// key retourne la clé correspondant à un kid
+func (s *KeySet) key(id string) (*Key, bool) {
+	key, exists := s.keys[id]
+	return key, exists
+}
+
+// decodeKey décode une clé en base64 et vérifie sa taille si size > 0
+func decodeKey(value string, size int) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("missing value")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 && len(data) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrUnknownCategory est renvoyée pour une catégorie sans mot dans la langue et
+// la difficulté demandées
+var ErrUnknownCategory = errors.New("no words in this category")
+
+// MaxCategoryLength borne la longueur d'un nom de catégorie
+const MaxCategoryLength = 30
+
+// CategoryCount décrit une catégorie du catalogue courant et ses mots activés
+type CategoryCount struct {
+	Name         string         `json:"name"`
+	Words        int            `json:"words"`
+	Difficulties map[string]int `json:"difficulties"` // nombre de mots par difficulté
+}
+
+// Categories liste les catégories du catalogue courant, par ordre alphabétique,
+// avec leur nombre de mots dans une langue (toutes si vide). Les mots sans
+// catégorie ne sont pas comptés.
+func Categories(language string) []CategoryCount {
+	counts := map[string]*CategoryCount{}
+	for lang, catalog := range *wordCatalogs.Load() {
+		if language != "" && lang != language {
+			continue
+		}
+		for difficulty, words := range catalog {
+			for _, w := range words {
+				if w.Category == "" {
+					continue
+				}
+				count, exists := counts[w.Category]
+				if !exists {
+					count = &CategoryCount{Name: w.Category, Difficulties: map[string]int{}}
+					for _, d := range Difficulties {
+						count.Difficulties[d] = 0
+					}
+					counts[w.Category] = count
+				}
+				count.Words++
+				count.Difficulties[difficulty]++
+			}
+		}
+	}
+
+	categories := make([]CategoryCount, 0, len(counts))
+	for _, count := range counts {
+		categories = append(categories, *count)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// validCategory vérifie qu'un nom de catégorie ne contient que des lettres
+// minuscules ASCII, des chiffres et des tirets
+func validCategory(category string) bool {
+	if len(category) > MaxCategoryLength {
+		return false
+	}
+	for _, r := range category {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package game_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// TestCategorySelection vérifie qu'une partie d'une catégorie ne tire que ses
+// mots dans la langue et la difficulté demandées, et qu'elle est classée avec elle
+func TestCategorySelection(t *testing.T) {
+	t.Cleanup(game.ResetWordCatalog)
+	store := memory.New()
+	_, err := game.ImportWords(store, []game.WordRecord{
+		{Word: "PIXEL", Hint: "Dot", Difficulty: "easy", Language: "en", Category: "technology"},
+		{Word: "SPRITE", Hint: "Image", Difficulty: "easy", Language: "en", Category: "technology"},
+		{Word: "MARIO", Hint: "Plumber", Difficulty: "easy", Language: "en", Category: "characters"},
+		{Word: "ARCADE", Hint: "Hall", Difficulty: "easy", Language: "en"},
+		{Word: "JOYSTICK", Hint: "Stick", Difficulty: "medium", Language: "en", Category: "technology"},
+		{Word: "CARTRIDGE", Hint: "Game", Difficulty: "hard", Language: "en"},
+		{Word: "MANETTE", Hint: "Contrôleur", Difficulty: "easy", Language: "fr", Category: "technology"},
+	}, game.SystemEditor)
+	if err != nil {
+		t.Fatalf("ImportWords: %v", err)
+	}
+	if err := game.RefreshWordCatalog(store); err != nil {
+		t.Fatalf("RefreshWordCatalog: %v", err)
+	}
+
+	drawn := map[string]bool{}
+	for seed := range int64(50) {
+		g, err := game.NewGameWithOptions(store, game.GameOptions{Difficulty: "easy", Category: "technology", Seed: &seed})
+		if err != nil {
+			t.Fatalf("NewGameWithOptions(technology): %v", err)
+		}
+		drawn[g.Word] = true
+		if entry := game.NewLeaderboardEntry(g, "alice", "alice"); entry.Category != "technology" {
+			t.Errorf("technology game ranked in category %q", entry.Category)
+		}
+	}
+	if len(drawn) != 2 || !drawn["PIXEL"] || !drawn["SPRITE"] {
+		t.Errorf("easy technology words drawn = %v, want PIXEL and SPRITE", drawn)
+	}
+
+	// Une catégorie sans mot dans la difficulté ou la langue n'est pas complétée par d'autres mots
+	for _, opts := range []game.GameOptions{
+		{Difficulty: "easy", Category: "sports"},
+		{Difficulty: "hard", Category: "technology"},
+		{Difficulty: "easy", Category: "characters", Language: "fr"},
+	} {
+		if _, err := game.NewGameWithOptions(store, opts); !errors.Is(err, game.ErrUnknownCategory) {
+			t.Errorf("NewGameWithOptions(%+v) = %v, want %v", opts, err, game.ErrUnknownCategory)
+		}
+	}
+
+	categories := game.Categories("en")
+	if len(categories) != 2 || categories[0].Name != "characters" || categories[1].Name != "technology" {
+		t.Fatalf("Categories(en) = %+v, want characters and technology", categories)
+	}
+	technology := categories[1]
+	if technology.Words != 3 || technology.Difficulties["easy"] != 2 || technology.Difficulties["medium"] != 1 || technology.Difficulties["hard"] != 0 {
+		t.Errorf("technology counts = %+v, want 3 words: 2 easy, 1 medium, 0 hard", technology)
+	}
+	if all := game.Categories(""); len(all) != 2 || all[1].Words != 4 {
+		t.Errorf("Categories() = %+v, want technology with 4 words across languages", all)
+	}
+}
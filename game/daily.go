@@ -0,0 +1,231 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Erreurs liées au défi quotidien
+var (
+	ErrDailyNotFound      = errors.New("daily challenge not found")
+	ErrDailyExists        = errors.New("daily challenge already exists")
+	ErrDailyAlreadyPlayed = errors.New("daily challenge already played")
+	ErrDailyGame          = errors.New("daily challenge games cannot be abandoned")
+)
+
+// DailyDifficulty est la difficulté des parties du défi quotidien
+const DailyDifficulty = "medium"
+
+// DailySecret est la clé secrète du serveur mêlée au tirage du mot de chaque
+// jour, définie au démarrage. Sans elle, le mot de demain se calculerait à
+// partir de la liste de mots publique.
+var DailySecret string
+
+// DailyWindow est le nombre de jours pendant lesquels le mot d'un défi
+// quotidien n'est pas proposé à nouveau, modifiable au démarrage
+var DailyWindow = 30
+
+// dayLayout est le format des jours du défi quotidien
+const dayLayout = "2006-01-02"
+
+// DailyChallenge est le mot commun à tous les joueurs d'une langue pour un jour
+// donné. Le mot et l'indice ne sont révélés qu'à travers les parties.
+type DailyChallenge struct {
+	Day        string    `json:"day"` // "2006-01-02" dans LeaderboardLocation
+	Language   string    `json:"language"`
+	Word       string    `json:"-"`
+	Hint       string    `json:"-"`
+	Difficulty string    `json:"difficulty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DailyPlay rattache la partie d'un joueur au défi d'un jour
+type DailyPlay struct {
+	Day      string
+	Language string
+	PlayerID string
+	GameID   string
+}
+
+// DailyStore décrit le stockage des défis quotidiens et des parties qui y sont jouées
+type DailyStore interface {
+	// CreateDailyChallenge enregistre le défi d'un jour (ErrDailyExists s'il
+	// existe déjà pour ce jour et cette langue)
+	CreateDailyChallenge(challenge *DailyChallenge) error
+	// GetDailyChallenge récupère le défi d'un jour (ErrDailyNotFound si absent)
+	GetDailyChallenge(day, language string) (*DailyChallenge, error)
+	// ListDailyWords retourne les mots des défis d'une langue entre les jours
+	// from (inclus) et to (exclu), dans l'ordre des jours
+	ListDailyWords(language, from, to string) ([]string, error)
+	// AddDailyPlay enregistre la partie d'un joueur (ErrDailyAlreadyPlayed s'il
+	// a déjà une partie pour ce défi)
+	AddDailyPlay(play DailyPlay) error
+	// GetDailyPlay récupère la partie d'un joueur pour un défi (ErrDailyNotFound si absente)
+	GetDailyPlay(day, language, playerID string) (*DailyPlay, error)
+}
+
+// DailyDay retourne le jour du défi quotidien contenant t
+func DailyDay(t time.Time) string {
+	return t.In(LeaderboardLocation).Format(dayLayout)
+}
+
+// GetDailyChallenge retourne le défi d'un jour et d'une langue, choisi par
+// SelectDailyWord et enregistré au premier appel (ErrUnsupportedLanguage si la
+// langue n'a pas de catalogue). Le défi retourné porte la langue de son mot.
+func GetDailyChallenge(store DailyStore, day, language string) (*DailyChallenge, error) {
+	if !SupportedLanguage(language) {
+		return nil, ErrUnsupportedLanguage
+	}
+	// Une langue sans mots se rabat sur le défi de la langue de secours, pour
+	// que ses joueurs soient classés avec ceux qui ont le même mot
+	_, language = catalogWords(language, DailyDifficulty)
+	start, err := time.Parse(dayLayout, day)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daily challenge day %q: %w", day, err)
+	}
+
+	challenge, err := store.GetDailyChallenge(day, language)
+	if !errors.Is(err, ErrDailyNotFound) {
+		return challenge, err
+	}
+
+	recent, err := store.ListDailyWords(language, start.AddDate(0, 0, -DailyWindow).Format(dayLayout), day)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := SelectDailyWord(day, language, recent)
+	challenge = &DailyChallenge{
+		Day:        day,
+		Language:   language,
+		Word:       selection.Word,
+		Hint:       selection.Hint,
+		Difficulty: DailyDifficulty,
+		CreatedAt:  time.Now().UTC(),
+	}
+	err = store.CreateDailyChallenge(challenge)
+	if errors.Is(err, ErrDailyExists) {
+		// Créé entre-temps par une autre requête : c'est lui qui fait foi
+		return store.GetDailyChallenge(day, language)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// SelectDailyWord choisit le mot d'un jour parmi les mots de difficulté
+// DailyDifficulty de la langue, en écartant ceux de exclude, du plus ancien au
+// plus récent. Le choix ne dépend que du jour, de la langue, des mots
+// disponibles et de DailySecret.
+func SelectDailyWord(day, language string, exclude []string) WordSelection {
+	catalog, used := catalogWords(language, DailyDifficulty)
+	words := append([]WordWithHint{}, catalog...)
+	sort.Slice(words, func(i, j int) bool {
+		return words[i].Word < words[j].Word
+	})
+
+	// Si la fenêtre est plus longue que la liste de mots, les mots les plus
+	// anciens de exclude redeviennent disponibles
+	var candidates []WordWithHint
+	for len(candidates) == 0 {
+		excluded := map[string]bool{}
+		for _, word := range exclude {
+			excluded[WordKey(word)] = true
+		}
+		for _, w := range words {
+			if !excluded[WordKey(w.Word)] {
+				candidates = append(candidates, w)
+			}
+		}
+		if len(exclude) == 0 {
+			break
+		}
+		exclude = exclude[1:]
+	}
+
+	mac := hmac.New(sha256.New, []byte(DailySecret))
+	mac.Write([]byte(language + "/" + day))
+	seed := int64(binary.BigEndian.Uint64(mac.Sum(nil)))
+	selected := candidates[NewSeededSource(seed).Intn(len(candidates))]
+	return WordSelection{Word: selected.Word, Hint: selected.Hint, Language: used}
+}
+
+// PlayDaily retourne la partie d'un joueur pour le défi du jour, créée à sa
+// première demande. Un joueur ne joue qu'une partie par défi : les demandes
+// suivantes retournent la même.
+func PlayDaily(games GameStore, daily DailyStore, challenge *DailyChallenge, playerID string) (*Game, error) {
+	for {
+		play, err := daily.GetDailyPlay(challenge.Day, challenge.Language, playerID)
+		if err == nil {
+			return games.GetGame(play.GameID)
+		}
+		if !errors.Is(err, ErrDailyNotFound) {
+			return nil, err
+		}
+
+		g, err := NewGameWithOptions(games, GameOptions{
+			Difficulty: challenge.Difficulty,
+			PlayerID:   playerID,
+			Language:   challenge.Language,
+			Word:       &WordSelection{Word: challenge.Word, Hint: challenge.Hint},
+			Daily:      challenge.Day,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = daily.AddDailyPlay(DailyPlay{
+			Day: challenge.Day, Language: challenge.Language, PlayerID: playerID, GameID: g.ID,
+		})
+		if errors.Is(err, ErrDailyAlreadyPlayed) {
+			// Une autre requête du joueur a gagné la course : garder sa partie
+			if err := games.DeleteGame(g.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	}
+}
+
+// DailySummary résume une partie du défi quotidien en émojis, sans dévoiler le
+// mot : une case par proposition (🟩 lettre juste, 🟥 lettre fausse, ⭐ mot
+// trouvé, 💥 mot faux) et les tentatives restantes
+func DailySummary(g *Game) string {
+	var squares strings.Builder
+	for _, letter := range g.Guesses {
+		if g.inWord(letter) {
+			squares.WriteString("🟩")
+		} else {
+			squares.WriteString("🟥")
+		}
+	}
+	for _, word := range g.WordGuesses {
+		if g.matchesWord(word) {
+			squares.WriteString("⭐")
+		} else {
+			squares.WriteString("💥")
+		}
+	}
+
+	result := "⏳"
+	switch g.Status {
+	case "won":
+		result = "🏆"
+	case "lost":
+		result = "💀"
+	}
+
+	return fmt.Sprintf("8-Bit Hangman %s %s %d/%d\n%s",
+		g.Daily, result, g.Remaining, getDifficultyAttempts(g.Difficulty), squares.String())
+}
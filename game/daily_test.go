@@ -0,0 +1,151 @@
+package game_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// setDailySecret remplace la clé du défi quotidien le temps d'un test
+func setDailySecret(t *testing.T, secret string) {
+	t.Helper()
+	previous := game.DailySecret
+	game.DailySecret = secret
+	t.Cleanup(func() { game.DailySecret = previous })
+}
+
+// dailyWords renvoie les mots tirés pour les jours suivant start
+func dailyWords(start time.Time, days int) []string {
+	words := make([]string, days)
+	for i := range words {
+		words[i] = game.SelectDailyWord(game.DailyDay(start.AddDate(0, 0, i)), "en", nil).Word
+	}
+	return words
+}
+
+// TestDailySecret vérifie que le mot du jour dépend de la clé du serveur, si
+// bien que la liste de mots seule ne permet pas de le prévoir
+func TestDailySecret(t *testing.T) {
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	setDailySecret(t, "first secret")
+	first := dailyWords(start, 10)
+	if again := dailyWords(start, 10); !slices.Equal(first, again) {
+		t.Fatalf("same secret drew %v, then %v", first, again)
+	}
+
+	setDailySecret(t, "second secret")
+	if second := dailyWords(start, 10); slices.Equal(first, second) {
+		t.Errorf("different secrets drew the same words %v", first)
+	}
+}
+
+// TestPlayDailyOnce vérifie qu'un compte ne joue qu'une partie par défi, même
+// quand il la demande plusieurs fois en même temps, et qu'elle ne peut pas être
+// abandonnée pour être rejouée
+func TestPlayDailyOnce(t *testing.T) {
+	setDailySecret(t, "secret")
+	store := memory.New()
+	day := game.DailyDay(time.Now())
+	challenge, err := game.GetDailyChallenge(store, day, "en")
+	if err != nil {
+		t.Fatalf("GetDailyChallenge: %v", err)
+	}
+	if again, err := game.GetDailyChallenge(store, day, "en"); err != nil || again.Word != challenge.Word {
+		t.Fatalf("GetDailyChallenge(again) = %v, %v; want the word %s", again, err, challenge.Word)
+	}
+
+	const requests = 8
+	var wg sync.WaitGroup
+	ids := make(chan string, requests)
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g, err := game.PlayDaily(store, store, challenge, "alice")
+			if err != nil {
+				t.Errorf("PlayDaily: %v", err)
+				return
+			}
+			ids <- g.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	first := <-ids
+	for id := range ids {
+		if id != first {
+			t.Fatalf("concurrent PlayDaily returned games %s and %s, want one", first, id)
+		}
+	}
+
+	other, err := game.PlayDaily(store, store, challenge, "bob")
+	if err != nil {
+		t.Fatalf("PlayDaily(bob): %v", err)
+	}
+	if other.ID == first || other.Word != challenge.Word || other.Daily != day {
+		t.Errorf("bob's game %s plays %s on %q, want another game of %s on %q", other.ID, other.Word, other.Daily, challenge.Word, day)
+	}
+
+	if err := game.DeleteGame(store, first); !errors.Is(err, game.ErrDailyGame) {
+		t.Errorf("DeleteGame(daily game) = %v, want %v", err, game.ErrDailyGame)
+	}
+}
+
+// TestDailyWindow vérifie qu'un mot du défi ne revient pas avant DailyWindow
+// jours, et que les mots les moins récents reviennent quand la liste est trop courte
+func TestDailyWindow(t *testing.T) {
+	t.Cleanup(game.ResetWordCatalog)
+	setDailySecret(t, "secret")
+	words := memory.New()
+	records := []game.WordRecord{
+		{Word: "PIXEL", Hint: "Dot", Difficulty: "easy", Language: "en"},
+		{Word: "CARTRIDGE", Hint: "Game", Difficulty: "hard", Language: "en"},
+	}
+	for _, word := range []string{"ARCADE", "CONSOLE", "JOYSTICK", "PINBALL", "SPRITE", "TETRIS"} {
+		records = append(records, game.WordRecord{Word: word, Hint: "hint", Difficulty: game.DailyDifficulty, Language: "en"})
+	}
+	if _, err := game.ImportWords(words, records, game.SystemEditor); err != nil {
+		t.Fatalf("ImportWords: %v", err)
+	}
+	if err := game.RefreshWordCatalog(words); err != nil {
+		t.Fatalf("RefreshWordCatalog: %v", err)
+	}
+
+	previous := game.DailyWindow
+	t.Cleanup(func() { game.DailyWindow = previous })
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	// Chaque fenêtre de distinct jours consécutifs n'a que des mots différents
+	check := func(window, distinct int) {
+		t.Helper()
+		game.DailyWindow = window
+		store := memory.New()
+		var picked []string
+		for i := range 40 {
+			challenge, err := game.GetDailyChallenge(store, game.DailyDay(start.AddDate(0, 0, i)), "en")
+			if err != nil {
+				t.Fatalf("GetDailyChallenge: %v", err)
+			}
+			picked = append(picked, challenge.Word)
+		}
+		for i := range picked {
+			recent := picked[max(0, i-distinct+1) : i+1]
+			seen := map[string]bool{}
+			for _, word := range recent {
+				if seen[word] {
+					t.Fatalf("window of %d days: %v repeats %s within %d days", window, recent, word, distinct)
+				}
+				seen[word] = true
+			}
+		}
+	}
+	check(3, 4)
+	// Six mots pour une fenêtre de dix jours : les mots tournent dans l'ordre
+	check(10, 6)
+}
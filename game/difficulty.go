@@ -0,0 +1,152 @@
+package game
+
+import (
+	"math"
+	"unicode"
+)
+
+// DifficultyAuto demande le classement automatique d'un mot selon son score de difficulté
+const DifficultyAuto = "auto"
+
+// MinWordStatsGames est le nombre de parties terminées à partir duquel le taux
+// de victoire observé d'un mot entre dans son score de difficulté
+const MinWordStatsGames = 10
+
+// Seuils de score séparant les niveaux de difficulté
+const (
+	mediumDifficultyScore = 32
+	hardDifficultyScore   = 60
+)
+
+// WordStats résume les parties terminées sur un mot
+type WordStats struct {
+	Language string `json:"language"`
+	Word     string `json:"word"`
+	Played   int    `json:"played"`
+	Won      int    `json:"won"`
+}
+
+// WinRate retourne la part des parties gagnées (0 sans partie jouée)
+func (s WordStats) WinRate() float64 {
+	if s.Played == 0 {
+		return 0
+	}
+	return float64(s.Won) / float64(s.Played)
+}
+
+// DifficultyScore détaille le score de difficulté d'un mot
+type DifficultyScore struct {
+	Score      float64  `json:"score"`      // de 0 (facile) à 100 (difficile)
+	Difficulty string   `json:"difficulty"` // niveau correspondant au score
+	Letters    int      `json:"letters"`
+	Distinct   int      `json:"distinct_letters"`
+	Rarity     float64  `json:"rarity"`             // multiplicateur de rareté moyen des lettres distinctes
+	WinRate    *float64 `json:"win_rate,omitempty"` // absent sous MinWordStatsGames parties
+}
+
+// ClassifyWord calcule le score de difficulté d'un mot. Un mot long, aux lettres
+// distinctes nombreuses et rares est plus difficile ; à partir de
+// MinWordStatsGames parties terminées, le taux de défaite observé compte pour
+// moitié.
+func ClassifyWord(word string, stats WordStats) DifficultyScore {
+	var result DifficultyScore
+	distinct := map[rune]bool{}
+	rarity := 0
+	for _, r := range sanitizeWord(word) {
+		if autoRevealed(r) {
+			continue
+		}
+		result.Letters++
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		key := letterKey(r, MatchFold)
+		if distinct[key] {
+			continue
+		}
+		distinct[key] = true
+		if m := letterFrequency[key]; m > 0 {
+			rarity += m
+		} else {
+			rarity++
+		}
+	}
+	result.Distinct = len(distinct)
+	if result.Distinct > 0 {
+		result.Rarity = math.Round(float64(rarity)/float64(result.Distinct)*100) / 100
+	}
+
+	score := 45*scale(float64(result.Letters), 4, 12) +
+		35*scale(float64(result.Distinct), 3, 10) +
+		20*scale(result.Rarity, 1, 3)
+	if stats.Played >= MinWordStatsGames {
+		winRate := stats.WinRate()
+		result.WinRate = &winRate
+		score = (score + 100*(1-winRate)) / 2
+	}
+	result.Score = math.Round(score*10) / 10
+
+	switch {
+	case result.Score >= hardDifficultyScore:
+		result.Difficulty = "hard"
+	case result.Score >= mediumDifficultyScore:
+		result.Difficulty = "medium"
+	default:
+		result.Difficulty = "easy"
+	}
+	return result
+}
+
+// scale ramène v de l'intervalle [low, high] à [0, 1]
+func scale(v, low, high float64) float64 {
+	return math.Max(0, math.Min(1, (v-low)/(high-low)))
+}
+
+// WordClassification compare la difficulté d'un mot à celle calculée par ClassifyWord
+type WordClassification struct {
+	WordEntry
+	Played         int             `json:"played"`
+	Won            int             `json:"won"`
+	Suggested      DifficultyScore `json:"suggested"`
+	Miscategorized bool            `json:"miscategorized"` // difficulté différente de la suggestion
+}
+
+// DifficultyReport classe les mots correspondant au filtre en tenant compte des
+// parties terminées. Seuls les mots mal classés sont retournés, sauf si all est vrai.
+func DifficultyReport(words WordStore, games GameStore, filter WordFilter, all bool) ([]WordClassification, error) {
+	entries, err := words.ListWords(filter)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := games.WordStats()
+	if err != nil {
+		return nil, err
+	}
+
+	// Les parties gardent le mot tel qu'il était : on les rapproche par clé
+	byKey := map[string]WordStats{}
+	for _, st := range stats {
+		key := st.Language + "/" + WordKey(st.Word)
+		total := byKey[key]
+		total.Played += st.Played
+		total.Won += st.Won
+		byKey[key] = total
+	}
+
+	report := []WordClassification{}
+	for _, entry := range entries {
+		st := byKey[entry.Language+"/"+WordKey(entry.Word)]
+		suggested := ClassifyWord(entry.Word, st)
+		classification := WordClassification{
+			WordEntry:      entry,
+			Played:         st.Played,
+			Won:            st.Won,
+			Suggested:      suggested,
+			Miscategorized: suggested.Difficulty != entry.Difficulty,
+		}
+		if all || classification.Miscategorized {
+			report = append(report, classification)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,46 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+)
+
+// TestClassifyWord vérifie le score de difficulté d'un mot et les seuils qui
+// séparent les niveaux, bornes comprises, avec ou sans parties jouées
+func TestClassifyWord(t *testing.T) {
+	tests := []struct {
+		word           string
+		played, won    int
+		wantScore      float64
+		wantDifficulty string
+		wantWinRate    bool
+	}{
+		// Sous MinWordStatsGames parties, le taux de victoire est ignoré
+		{"EEEE", game.MinWordStatsGames - 1, 0, 0, "easy", false},
+		{"EEEE", 25, 9, 32, "medium", true},
+		{"EEEE", 25, 10, 30, "easy", true},
+		{"ZZZZ", 0, 0, 20, "easy", false},
+		{"ZZZZ", 10, 0, 60, "hard", true},
+		{"ZZZZ", 10, 1, 55, "medium", true},
+		{"PIXEL", 0, 0, 29.6, "easy", false},
+		{"JUKEBOX", 0, 0, 56.9, "medium", false},
+		{"JUKEBOXES", 0, 0, 71.9, "hard", false},
+		// Séparateurs ignorés, accents confondus
+		{"CASSE-BRIQUES", 0, 0, 85, "hard", false},
+		{"L'ÉTÉ", 0, 0, 3.3, "easy", false},
+	}
+	for _, tt := range tests {
+		got := game.ClassifyWord(tt.word, game.WordStats{Played: tt.played, Won: tt.won})
+		if got.Score != tt.wantScore || got.Difficulty != tt.wantDifficulty || (got.WinRate != nil) != tt.wantWinRate {
+			t.Errorf("ClassifyWord(%s, %d/%d) = %v %s (win rate %v), want %v %s (win rate %v)",
+				tt.word, tt.won, tt.played, got.Score, got.Difficulty, got.WinRate != nil, tt.wantScore, tt.wantDifficulty, tt.wantWinRate)
+		}
+	}
+
+	details := game.ClassifyWord("L'ÉTÉ", game.WordStats{})
+	if details.Letters != 4 || details.Distinct != 3 || details.Rarity != 1.33 {
+		t.Errorf("ClassifyWord(L'ÉTÉ) counts %d letters, %d distinct, rarity %v; want 4, 3, 1.33",
+			details.Letters, details.Distinct, details.Rarity)
+	}
+}
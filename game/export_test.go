@@ -0,0 +1,6 @@
+package game
+
+// ResetWordCatalog rétablit le catalogue intégré après un test qui l'a remplacé
+func ResetWordCatalog() {
+	wordCatalogs.Store(&builtinCatalog)
+}
@@ -0,0 +1,181 @@
+package game
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LeaderboardStreamTop est le nombre d'entrées du haut du classement suivies
+// par le flux, modifiable au démarrage
+var LeaderboardStreamTop = 10
+
+const (
+	// leaderboardHistory est le nombre d'événements gardés pour les reprises
+	leaderboardHistory = 100
+	// leaderboardBacklog est le nombre d'événements en attente au-delà duquel
+	// un abonné trop lent est déconnecté
+	leaderboardBacklog = 16
+)
+
+// Types d'événements du flux du classement
+const (
+	LeaderboardSnapshot = "snapshot" // état du haut du classement à l'abonnement
+	LeaderboardUpdate   = "update"   // un score est entré dans le haut du classement
+)
+
+// LeaderboardEvent décrit le haut du classement d'une difficulté
+type LeaderboardEvent struct {
+	ID         string        `json:"-"`
+	Type       string        `json:"-"`
+	Difficulty string        `json:"difficulty"`
+	Entry      *RankedEntry  `json:"entry,omitempty"` // entrée ajoutée, pour un événement "update"
+	Top        []RankedEntry `json:"top"`
+}
+
+// LeaderboardSubscription est un abonnement au flux du classement
+type LeaderboardSubscription struct {
+	// Events reçoit les événements publiés ; il est fermé si l'abonné prend
+	// trop de retard, qui doit alors se réabonner à partir du dernier ID reçu
+	Events <-chan LeaderboardEvent
+	// Missed contient les événements publiés après celui demandé à la reprise
+	Missed []LeaderboardEvent
+	// Resumed est faux si la reprise est impossible (ID inconnu ou trop ancien) :
+	// l'abonné doit repartir d'un état complet
+	Resumed bool
+	// LastID est l'ID du dernier événement publié avant l'abonnement
+	LastID string
+
+	ch chan LeaderboardEvent
+}
+
+// leaderboardFeed diffuse les changements du haut du classement aux abonnés de
+// ce processus et garde les derniers pour les reprises
+type leaderboardFeed struct {
+	mu          sync.Mutex
+	boot        string // distingue les IDs d'un processus à l'autre
+	seq         int
+	history     []LeaderboardEvent
+	subscribers map[chan LeaderboardEvent]struct{}
+
+	// publishMu sérialise le calcul et la publication des événements pour
+	// qu'ils soient diffusés dans l'ordre des classements observés
+	publishMu sync.Mutex
+}
+
+var leaderboardUpdates = leaderboardFeed{
+	boot:        strconv.FormatInt(time.Now().UnixMilli(), 36),
+	subscribers: make(map[chan LeaderboardEvent]struct{}),
+}
+
+// SubscribeLeaderboard s'abonne aux changements du haut du classement. Si
+// lastEventID est l'ID d'un événement encore en mémoire, les événements suivants
+// sont rejoués dans Missed.
+func SubscribeLeaderboard(lastEventID string) *LeaderboardSubscription {
+	f := &leaderboardUpdates
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub := &LeaderboardSubscription{
+		LastID: f.id(f.seq),
+		ch:     make(chan LeaderboardEvent, leaderboardBacklog),
+	}
+	sub.Events = sub.ch
+	f.subscribers[sub.ch] = struct{}{}
+
+	if lastEventID == "" {
+		return sub
+	}
+	boot, seq, found := strings.Cut(lastEventID, "-")
+	n, err := strconv.Atoi(seq)
+	if !found || err != nil || boot != f.boot || n > f.seq {
+		return sub
+	}
+	// Les événements manqués doivent tous être encore en mémoire
+	if n < f.seq && (len(f.history) == 0 || n < f.seq-len(f.history)) {
+		return sub
+	}
+	sub.Resumed = true
+	sub.Missed = append(sub.Missed, f.history[len(f.history)-(f.seq-n):]...)
+	return sub
+}
+
+// Close met fin à l'abonnement
+func (s *LeaderboardSubscription) Close() {
+	f := &leaderboardUpdates
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Déjà fermé si l'abonné a pris trop de retard
+	if _, exists := f.subscribers[s.ch]; exists {
+		delete(f.subscribers, s.ch)
+		close(s.ch)
+	}
+}
+
+// id formate l'ID de l'événement numéro seq
+func (f *leaderboardFeed) id(seq int) string {
+	return f.boot + "-" + strconv.Itoa(seq)
+}
+
+// publish numérote un événement, le garde pour les reprises et le diffuse
+func (f *leaderboardFeed) publish(event LeaderboardEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	event.ID = f.id(f.seq)
+	event.Type = LeaderboardUpdate
+	f.history = append(f.history, event)
+	if len(f.history) > leaderboardHistory {
+		f.history = f.history[len(f.history)-leaderboardHistory:]
+	}
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// LeaderboardTop retourne les LeaderboardStreamTop premières entrées du
+// classement de tous les temps d'une difficulté
+func LeaderboardTop(store LeaderboardStore, difficulty string) ([]RankedEntry, error) {
+	page, err := QueryLeaderboard(store, LeaderboardQuery{
+		Difficulty: difficulty,
+		Limit:      LeaderboardStreamTop,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Entries, nil
+}
+
+// publishLeaderboardChange diffuse le haut du classement de la difficulté d'une
+// entrée ajoutée si elle y figure. Une erreur est seulement journalisée car
+// l'ajout lui-même a réussi.
+func publishLeaderboardChange(store LeaderboardStore, entry LeaderboardEntry) {
+	leaderboardUpdates.publishMu.Lock()
+	defer leaderboardUpdates.publishMu.Unlock()
+
+	top, err := LeaderboardTop(store, entry.Difficulty)
+	if err != nil {
+		log.Printf("publishing leaderboard change: %v", err)
+		return
+	}
+	for i := range top {
+		if top[i].ID == entry.ID {
+			leaderboardUpdates.publish(LeaderboardEvent{
+				Difficulty: entry.Difficulty,
+				Entry:      &top[i],
+				Top:        top,
+			})
+			return
+		}
+	}
+}
@@ -1,94 +1,290 @@
 package game
 
 import (
+	"errors"
 	"strings"
-	"sync"
 
 	"github.com/N95Ryan/8bit-hangman-back/utils"
 )
 
 // Game représente l'état d'une partie de pendu
 type Game struct {
-	ID         string   `json:"id"`
-	Word       string   `json:"word"`
-	Guesses    []string `json:"guesses"`
-	Remaining  int      `json:"remaining"`
-	Status     string   `json:"status"` // "in_progress", "won", "lost"
-	Score      int      `json:"score"`
-	Difficulty string   `json:"difficulty"`
-	Hint       string   `json:"hint"`
-}
-
-// Stockage en mémoire des parties
-var (
-	games      = make(map[string]*Game)
-	gamesMutex sync.RWMutex
-)
+	ID          string   `json:"id"`
+	Word        string   `json:"word"`
+	Guesses     []string `json:"guesses"`
+	WordGuesses []string `json:"word_guesses"` // mots ou phrases proposés en entier
+	Remaining   int      `json:"remaining"`
+	Status      string   `json:"status"` // "in_progress", "won", "lost"
+	Score       int      `json:"score"`
+	Difficulty  string   `json:"difficulty"`
+	Hint        string   `json:"hint"`
+	ShareCode   string   `json:"share_code,omitempty"` // code court optionnel pour partager la partie
+	PlayerID    string   `json:"player_id,omitempty"`  // joueur authentifié qui a créé la partie (vide si anonyme)
+	Matching    string   `json:"matching"`             // "fold" (accents ignorés) ou "strict"
+	Language    string   `json:"language"`             // langue du catalogue d'où vient le mot
+	Category    string   `json:"category,omitempty"`   // catégorie choisie à la création (vide = toutes)
+	Daily       string   `json:"daily,omitempty"`      // jour du défi quotidien joué ("2006-01-02"), vide sinon
+	Seed        int64    `json:"seed"`                 // graine du tirage du mot, pour rejouer la partie (0 si le mot est imposé)
+	Match       string   `json:"match,omitempty"`      // course multijoueur à laquelle appartient la partie
+	Version     int      `json:"-"`                    // incrémentée à chaque enregistrement, voir GameStore.SaveGame
+}
+
+// GameOptions regroupe les paramètres de création d'une partie
+type GameOptions struct {
+	Difficulty string         // "easy", "medium" (par défaut) ou "hard"
+	Share      bool           // attribue un code de partage court à la partie
+	PlayerID   string         // rattache la partie à un joueur, seul autorisé à soumettre son score
+	Matching   string         // "fold" ou "strict" (DefaultMatching si vide)
+	Language   string         // langue du mot et de l'indice (DefaultLanguage si vide)
+	Category   string         // catégorie du mot (toutes si vide)
+	Word       *WordSelection // mot imposé au lieu d'un mot aléatoire (défi quotidien)
+	Daily      string         // jour du défi quotidien auquel appartient la partie
+	Seed       *int64         // graine du tirage du mot (tirée de la source aléatoire si nil, ignorée si Word est imposé)
+	Match      string         // course multijoueur à laquelle appartient la partie
+}
 
 // NewGame crée une nouvelle partie avec un mot aléatoire
-func NewGame() *Game {
-	return NewGameWithDifficulty("medium")
+func NewGame(store GameStore) (*Game, error) {
+	return NewGameWithDifficulty(store, "medium")
 }
 
 // NewGameWithDifficulty crée une nouvelle partie avec un niveau de difficulté spécifié
-func NewGameWithDifficulty(difficulty string) *Game {
-	wordSelection := GetRandomWordByDifficulty(difficulty)
+func NewGameWithDifficulty(store GameStore, difficulty string) (*Game, error) {
+	return NewGameWithOptions(store, GameOptions{Difficulty: difficulty})
+}
+
+// NewGameWithOptions crée une nouvelle partie selon les options données
+// (ErrUnsupportedLanguage si la langue n'a pas de catalogue, ErrUnknownCategory
+// si la catégorie n'a aucun mot dans cette langue et cette difficulté)
+func NewGameWithOptions(store GameStore, opts GameOptions) (*Game, error) {
+	difficulty := opts.Difficulty
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	matching := opts.Matching
+	if matching == "" {
+		matching = DefaultMatching
+	}
+	language := opts.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+	if !SupportedLanguage(language) {
+		return nil, ErrUnsupportedLanguage
+	}
+
+	var wordSelection WordSelection
+	var seed int64
+	var err error
+	if opts.Word != nil {
+		wordSelection = *opts.Word
+	} else {
+		// Chaque partie tirée a sa propre graine : la rejouer avec la même graine
+		// tire le même mot
+		seed = randomSource.Int63()
+		if opts.Seed != nil {
+			seed = *opts.Seed
+		}
+
+		if wordSelection, err = SelectWord(NewSeededSource(seed), language, difficulty, opts.Category); err != nil {
+			return nil, err
+		}
+	}
+	// Une langue sans mots se rabat sur une autre : la partie est jouée et
+	// classée dans la langue de son mot
+	if wordSelection.Language != "" {
+		language = wordSelection.Language
+	}
 	game := &Game{
-		ID:         utils.GenerateID(),
-		Word:       wordSelection.Word,
-		Guesses:    []string{},
-		Remaining:  getDifficultyAttempts(difficulty),
-		Status:     "in_progress",
-		Score:      0,
-		Difficulty: difficulty,
-		Hint:       wordSelection.Hint,
+		Word:        wordSelection.Word,
+		Guesses:     []string{},
+		WordGuesses: []string{},
+		Remaining:   getDifficultyAttempts(difficulty),
+		Status:      "in_progress",
+		Score:       0,
+		Difficulty:  difficulty,
+		Hint:        wordSelection.Hint,
+		PlayerID:    opts.PlayerID,
+		Matching:    matching,
+		Language:    language,
+		Category:    opts.Category,
+		Daily:       opts.Daily,
+		Seed:        seed,
+		Match:       opts.Match,
 	}
 
-	// Stocker la partie en mémoire
-	gamesMutex.Lock()
-	games[game.ID] = game
-	gamesMutex.Unlock()
+	// Enregistrer la partie, avec de nouveaux identifiants en cas de collision
+	err = utils.RetryOnDuplicateID(func() error {
+		game.ID = utils.NewID()
+		if opts.Share {
+			game.ShareCode = utils.GenerateShortCode()
+		}
+		return store.CreateGame(game)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return game
+	return game, nil
 }
 
 // GetGame récupère une partie par son ID
-func GetGame(id string) (*Game, bool) {
-	gamesMutex.RLock()
-	defer gamesMutex.RUnlock()
+func GetGame(store GameStore, id string) (*Game, error) {
+	return store.GetGame(id)
+}
 
-	game, exists := games[id]
-	return game, exists
+// GetGameByShareCode récupère une partie par son code de partage
+func GetGameByShareCode(store GameStore, code string) (*Game, error) {
+	return store.GetGameByShareCode(strings.ToUpper(strings.TrimSpace(code)))
 }
 
-// DeleteGame supprime une partie
-func DeleteGame(id string) bool {
-	gamesMutex.Lock()
-	defer gamesMutex.Unlock()
+// maxUpdateAttempts borne les relectures d'une partie modifiée par un autre serveur
+const maxUpdateAttempts = 5
 
-	if _, exists := games[id]; !exists {
-		return false
+// UpdateGame charge une partie, lui applique fn puis l'enregistre et diffuse le
+// nouvel état aux abonnés de SubscribeGame. Les appels concurrents sur une même
+// partie sont sérialisés : fn voit toujours l'état laissé par l'appel précédent.
+// Si un autre serveur partageant la base a enregistré la partie entre-temps, elle
+// est relue et fn réappliqué (ErrGameConflict après maxUpdateAttempts essais).
+// Si fn renvoie une erreur, rien n'est enregistré.
+func UpdateGame(store GameStore, id string, fn func(*Game) error) (*Game, error) {
+	unlock := gameLocks.lock(id)
+	defer unlock()
+
+	for range maxUpdateAttempts {
+		g, err := store.GetGame(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fn(g); err != nil {
+			return nil, err
+		}
+
+		err = store.SaveGame(g)
+		if errors.Is(err, ErrGameConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Sous le verrou : les abonnés reçoivent les états dans l'ordre
+		gameUpdates.publish(g)
+		return g, nil
 	}
+	return nil, ErrGameConflict
+}
 
-	delete(games, id)
-	return true
+// SubmitGuess propose une lettre pour une partie en cours et retourne l'état
+// mis à jour et si la lettre est dans le mot (ErrInvalidLetter si ce n'est pas
+// une lettre unique, ErrGameOver si la partie est terminée, ErrMatchGame pour
+// une partie de course, jouée par SubmitMatchGuess)
+func SubmitGuess(store GameStore, id string, letter string) (*Game, bool, error) {
+	if !ValidLetter(letter) {
+		return nil, false, ErrInvalidLetter
+	}
+
+	var found bool
+	g, err := UpdateGame(store, id, func(g *Game) error {
+		if g.Match != "" {
+			return ErrMatchGame
+		}
+		// Vérifié sous le verrou : une autre requête a pu terminer la partie
+		if g.Status != "in_progress" {
+			return ErrGameOver
+		}
+
+		found = g.MakeGuess(letter)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return g, found, nil
+}
+
+// SubmitWordGuess propose le mot (ou la phrase) entier pour une partie en cours et
+// retourne l'état mis à jour et si la proposition est juste (ErrGameOver si la
+// partie est terminée, ErrMatchGame pour une partie de course)
+func SubmitWordGuess(store GameStore, id string, word string) (*Game, bool, error) {
+	var found bool
+	g, err := UpdateGame(store, id, func(g *Game) error {
+		if g.Match != "" {
+			return ErrMatchGame
+		}
+		if g.Status != "in_progress" {
+			return ErrGameOver
+		}
+
+		found = g.GuessWord(word)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return g, found, nil
+}
+
+// DeleteGame supprime une partie et ferme ses abonnements (ErrDailyGame pour
+// une partie du défi quotidien, qui ne peut pas être rejouée, ErrMatchGame pour
+// une partie de course)
+func DeleteGame(store GameStore, id string) error {
+	unlock := gameLocks.lock(id)
+	defer unlock()
+
+	g, err := store.GetGame(id)
+	if err != nil {
+		return err
+	}
+	if g.Daily != "" {
+		return ErrDailyGame
+	}
+	if g.Match != "" {
+		return ErrMatchGame
+	}
+
+	if err := store.DeleteGame(id); err != nil {
+		return err
+	}
+
+	gameUpdates.closeGame(id)
+	return nil
 }
 
-// MakeGuess traite une tentative de lettre
+// MakeGuess traite une tentative de lettre. Elle ne fait rien si la partie est
+// terminée ; l'appelant doit sérialiser les appels (voir SubmitGuess).
 func (g *Game) MakeGuess(letter string) bool {
-	letter = sanitizeLetter(letter)
+	if g.Status != "in_progress" {
+		return false
+	}
 
-	// Vérifier si la lettre a déjà été essayée
+	letter, ok := normalizeLetter(letter)
+	if !ok {
+		return false
+	}
+	key := letterKey([]rune(letter)[0], g.Matching)
+
+	// Vérifier si la lettre (ou une variante équivalente) a déjà été essayée
 	for _, l := range g.Guesses {
-		if l == letter {
+		if g.guessKey(l) == key {
 			return false
 		}
 	}
 
 	g.Guesses = append(g.Guesses, letter)
 
-	// Vérifier si la lettre est dans le mot
-	if !strings.Contains(g.Word, letter) {
+	// Compter les lettres du mot révélées par cette proposition
+	occurrences := 0
+	for _, char := range g.Word {
+		if !autoRevealed(char) && letterKey(char, g.Matching) == key {
+			occurrences++
+		}
+	}
+
+	if occurrences == 0 {
 		g.Remaining--
 		if g.Remaining <= 0 {
 			g.Status = "lost"
@@ -97,7 +293,7 @@ func (g *Game) MakeGuess(letter string) bool {
 	}
 
 	// Calculer le score pour cette lettre
-	g.Score += CalculateScore(g.Word, letter)
+	g.Score += CalculateLetterScore(letter, occurrences)
 
 	// Vérifier si le joueur a gagné
 	if g.IsWon() {
@@ -108,37 +304,128 @@ func (g *Game) MakeGuess(letter string) bool {
 	return true
 }
 
+// WordGuessPenalty est le nombre de tentatives perdues pour un mot entier
+// erroné, modifiable au démarrage
+var WordGuessPenalty = 2
+
+// GuessWord traite une proposition du mot entier. Un mot juste gagne la partie
+// immédiatement avec un bonus pour chaque lettre encore cachée ; un mot faux coûte
+// WordGuessPenalty tentatives. Comme MakeGuess, elle ne fait rien si la partie
+// est terminée ou si le mot a déjà été proposé.
+func (g *Game) GuessWord(word string) bool {
+	if g.Status != "in_progress" {
+		return false
+	}
+
+	word = sanitizeWord(word)
+	if word == "" || utils.Contains(g.WordGuesses, word) {
+		return false
+	}
+
+	g.WordGuesses = append(g.WordGuesses, word)
+
+	if !g.matchesWord(word) {
+		g.Remaining = max(0, g.Remaining-WordGuessPenalty)
+		if g.Remaining == 0 {
+			g.Status = "lost"
+		}
+		return false
+	}
+
+	g.Score += CalculateWordGuessBonus(g.hiddenLetters())
+	g.Score += CalculateBonusScore(g.Remaining)
+	g.Status = "won"
+	return true
+}
+
+// hiddenLetters compte les lettres du mot qui ne sont pas encore révélées
+func (g *Game) hiddenLetters() int {
+	hidden := 0
+	for _, char := range g.Word {
+		if !g.revealed(char) {
+			hidden++
+		}
+	}
+	return hidden
+}
+
+// IsOver indique si la partie est terminée (gagnée ou perdue)
+func (g *Game) IsOver() bool {
+	return g.Status != "in_progress"
+}
+
 // IsWon vérifie si toutes les lettres du mot ont été trouvées
 func (g *Game) IsWon() bool {
-	for _, char := range g.Word {
-		if char != ' ' && !utils.Contains(g.Guesses, string(char)) {
-			return false
+	return g.hiddenLetters() == 0
+}
+
+// WrongGuesses compte les lettres proposées absentes du mot et les mots entiers erronés
+func (g *Game) WrongGuesses() int {
+	wrong := 0
+	for _, letter := range g.Guesses {
+		if !g.inWord(letter) {
+			wrong++
 		}
 	}
-	return true
+	for _, word := range g.WordGuesses {
+		if !g.matchesWord(word) {
+			wrong++
+		}
+	}
+	return wrong
 }
 
 // GetMaskedWord retourne le mot avec les lettres non devinées masquées
+// (le mot entier une fois la partie gagnée). Espaces, traits d'union et
+// apostrophes sont toujours affichés.
 func (g *Game) GetMaskedWord() string {
-	masked := ""
+	if g.Status == "won" {
+		return g.Word
+	}
+
+	var masked strings.Builder
 	for _, char := range g.Word {
-		if char == ' ' {
-			masked += " "
-		} else if utils.Contains(g.Guesses, string(char)) {
-			masked += string(char)
+		if g.revealed(char) {
+			masked.WriteRune(char)
 		} else {
-			masked += "_"
+			masked.WriteByte('_')
 		}
 	}
-	return masked
+	return masked.String()
 }
 
-// sanitizeLetter normalise une lettre (majuscule)
-func sanitizeLetter(letter string) string {
-	if len(letter) == 0 {
-		return ""
+// revealed indique si un caractère du mot est affiché : séparateur ou lettre
+// correspondant à une proposition selon le mode de comparaison de la partie
+func (g *Game) revealed(char rune) bool {
+	if autoRevealed(char) {
+		return true
 	}
-	return strings.ToUpper(string(letter[0]))
+	key := letterKey(char, g.Matching)
+	for _, l := range g.Guesses {
+		if g.guessKey(l) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// inWord indique si une lettre proposée figure dans le mot
+func (g *Game) inWord(letter string) bool {
+	key := g.guessKey(letter)
+	return strings.ContainsFunc(g.Word, func(char rune) bool { return letterKey(char, g.Matching) == key })
+}
+
+// guessKey retourne la forme de comparaison d'une lettre proposée
+func (g *Game) guessKey(letter string) rune {
+	for _, r := range letter {
+		return letterKey(r, g.Matching)
+	}
+	return 0
+}
+
+// matchesWord indique si un mot proposé correspond au mot de la partie
+func (g *Game) matchesWord(word string) bool {
+	return foldWord(word, g.Matching) == foldWord(g.Word, g.Matching)
 }
 
 // getDifficultyAttempts retourne le nombre de tentatives selon la difficulté
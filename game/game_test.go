@@ -0,0 +1,169 @@
+package game_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// TestConcurrentGuesses soumet toutes les lettres d'un pangramme en parallèle :
+// aucune proposition ne doit être perdue (à lancer avec go test -race)
+func TestConcurrentGuesses(t *testing.T) {
+	store := memory.New()
+	g, err := game.NewGameWithOptions(store, game.GameOptions{
+		Difficulty: "hard",
+		Word:       &game.WordSelection{Word: "PACK MY BOX WITH FIVE DOZEN LIQUOR JUGS", Hint: "Pangram"},
+	})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+
+	updates, cancel := game.SubscribeGame(g.ID)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for letter := 'A'; letter <= 'Z'; letter++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, found, err := game.SubmitGuess(store, g.ID, string(letter)); err != nil || !found {
+				t.Errorf("SubmitGuess(%c) = %v, %v; want found", letter, found, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	final, err := game.GetGame(store, g.ID)
+	if err != nil {
+		t.Fatalf("GetGame: %v", err)
+	}
+	if len(final.Guesses) != 26 || final.Status != "won" {
+		t.Errorf("after 26 concurrent guesses: %d guesses, status %q; want 26, won", len(final.Guesses), final.Status)
+	}
+	if final.Version != 26 {
+		t.Errorf("version = %d, want 26", final.Version)
+	}
+
+	// Le dernier état diffusé est l'état final
+	if latest := <-updates; len(latest.Guesses) != 26 {
+		t.Errorf("last published state has %d guesses, want 26", len(latest.Guesses))
+	}
+}
+
+// TestImposedWordSeed vérifie qu'une partie au mot imposé n'a pas de graine,
+// contrairement à une partie dont le mot est tiré
+func TestImposedWordSeed(t *testing.T) {
+	store := memory.New()
+	imposed, err := game.NewGameWithOptions(store, game.GameOptions{
+		Word: &game.WordSelection{Word: "PIXEL", Hint: "Dot"},
+	})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+	if imposed.Seed != 0 {
+		t.Errorf("seed of a game with an imposed word = %d, want 0", imposed.Seed)
+	}
+
+	seed := int64(42)
+	drawn, err := game.NewGameWithOptions(store, game.GameOptions{Seed: &seed})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+	if drawn.Seed != seed {
+		t.Errorf("seed of a drawn game = %d, want %d", drawn.Seed, seed)
+	}
+}
+
+// TestGuessLetters vérifie qu'une lettre est validée après normalisation : un
+// accent saisi séparément est accepté, un caractère qui n'est pas une lettre est
+// refusé sans coûter d'essai
+func TestGuessLetters(t *testing.T) {
+	store := memory.New()
+	g, err := game.NewGameWithOptions(store, game.GameOptions{
+		Matching: game.MatchStrict,
+		Word:     &game.WordSelection{Word: "L'ÉTÉ", Hint: "Season"},
+	})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+
+	for _, letter := range []string{"-", "'", "2", "", "AB", "E\u0301\u0301"} {
+		if _, _, err := game.SubmitGuess(store, g.ID, letter); !errors.Is(err, game.ErrInvalidLetter) {
+			t.Errorf("SubmitGuess(%q) = %v, want %v", letter, err, game.ErrInvalidLetter)
+		}
+	}
+
+	updated, found, err := game.SubmitGuess(store, g.ID, "e\u0301")
+	if err != nil || !found {
+		t.Fatalf("SubmitGuess(decomposed É) = %v, %v; want found", found, err)
+	}
+	if len(updated.Guesses) != 1 || updated.Guesses[0] != "É" || updated.Remaining != g.Remaining {
+		t.Errorf("after the guesses: %q with %d attempts left, want [É] with %d", updated.Guesses, updated.Remaining, g.Remaining)
+	}
+}
+
+// TestGuessWord vérifie le score d'un mot entier trouvé, bonus compris pour
+// chaque lettre encore cachée, et la pénalité d'un mot faux
+func TestGuessWord(t *testing.T) {
+	store := memory.New()
+	newGame := func(word string) *game.Game {
+		t.Helper()
+		g, err := game.NewGameWithOptions(store, game.GameOptions{
+			Matching: game.MatchFold,
+			Word:     &game.WordSelection{Word: word, Hint: "hint"},
+		})
+		if err != nil {
+			t.Fatalf("NewGameWithOptions: %v", err)
+		}
+		return g
+	}
+	guessWord := func(g *game.Game, word string, wantFound bool) *game.Game {
+		t.Helper()
+		updated, found, err := game.SubmitWordGuess(store, g.ID, word)
+		if err != nil || found != wantFound {
+			t.Fatalf("SubmitWordGuess(%q) = %v, %v; want %v", word, found, err, wantFound)
+		}
+		return updated
+	}
+
+	// P vaut 30 points, puis les 4 lettres cachées 30 chacune et les 6 essais restants 50 chacun
+	g := newGame("PIXEL")
+	if _, _, err := game.SubmitGuess(store, g.ID, "P"); err != nil {
+		t.Fatalf("SubmitGuess: %v", err)
+	}
+	won := guessWord(g, " pixel ", true)
+	if won.Status != "won" || won.Score != 30+4*30+6*50 || won.WrongGuesses() != 0 {
+		t.Errorf("after the right word: %s with score %d and %d wrong guesses, want won with %d and 0",
+			won.Status, won.Score, won.WrongGuesses(), 30+4*30+6*50)
+	}
+	if _, _, err := game.SubmitWordGuess(store, g.ID, "PIXEL"); !errors.Is(err, game.ErrGameOver) {
+		t.Errorf("SubmitWordGuess(finished game) = %v, want %v", err, game.ErrGameOver)
+	}
+
+	// Accents, casse, espaces et apostrophes typographiques sont ignorés
+	phrase := guessWord(newGame("L'ÉTÉ INDIEN"), "l’ete indien", true)
+	if phrase.Status != "won" || phrase.Score != 10*30+6*50 {
+		t.Errorf("after the right phrase: %s with score %d, want won with %d", phrase.Status, phrase.Score, 10*30+6*50)
+	}
+
+	// Un mot faux coûte WordGuessPenalty essais, une seule fois par mot
+	g = newGame("SPRITE")
+	wrong := guessWord(g, "SPRINT", false)
+	wrong = guessWord(wrong, "sprint", false)
+	if wrong.Remaining != g.Remaining-game.WordGuessPenalty || wrong.WrongGuesses() != 1 || wrong.Score != 0 {
+		t.Errorf("after a wrong word twice: %d attempts, %d wrong guesses, score %d; want %d, 1, 0",
+			wrong.Remaining, wrong.WrongGuesses(), wrong.Score, g.Remaining-game.WordGuessPenalty)
+	}
+
+	// La pénalité ne fait pas descendre les essais sous zéro
+	previous := game.WordGuessPenalty
+	game.WordGuessPenalty = 100
+	t.Cleanup(func() { game.WordGuessPenalty = previous })
+	lost := guessWord(newGame("ARCADE"), "ARCADES", false)
+	if lost.Status != "lost" || lost.Remaining != 0 {
+		t.Errorf("after a wrong word costing every attempt: %s with %d attempts, want lost with 0", lost.Status, lost.Remaining)
+	}
+}
@@ -0,0 +1,54 @@
+package game
+
+import (
+	"errors"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// ErrUnsupportedLanguage est renvoyée pour une langue sans catalogue de mots
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// DefaultLanguage est la langue des parties quand le joueur n'en choisit pas,
+// modifiable au démarrage
+var DefaultLanguage = "en"
+
+// Languages liste les langues du catalogue de mots courant (codes ISO 639-1),
+// par ordre alphabétique
+func Languages() []string {
+	catalog := *wordCatalogs.Load()
+	codes := make([]string, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// SupportedLanguage indique si une langue dispose d'un catalogue de mots
+func SupportedLanguage(code string) bool {
+	_, exists := (*wordCatalogs.Load())[code]
+	return exists
+}
+
+// MatchLanguage choisit la langue disponible la plus proche d'un en-tête
+// Accept-Language ("fr-CA,fr;q=0.9,en;q=0.8"), DefaultLanguage si aucune ne convient
+func MatchLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+
+	codes := Languages()
+	supported := make([]language.Tag, len(codes))
+	for i, code := range codes {
+		supported[i] = language.Make(code)
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage
+	}
+	return codes[index]
+}
@@ -0,0 +1,78 @@
+package game_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// TestLanguageFallback vérifie qu'une partie se rabat sur la difficulté
+// "medium" puis sur la langue par défaut quand sa langue manque de mots, et
+// qu'elle est alors classée dans la langue de son mot
+func TestLanguageFallback(t *testing.T) {
+	t.Cleanup(game.ResetWordCatalog)
+	store := memory.New()
+	_, err := game.ImportWords(store, []game.WordRecord{
+		{Word: "PIXEL", Hint: "Dot", Difficulty: "easy", Language: "en"},
+		{Word: "JOYSTICK", Hint: "Stick", Difficulty: "medium", Language: "en"},
+		{Word: "CARTRIDGE", Hint: "Game", Difficulty: "hard", Language: "en"},
+		{Word: "MANETTE", Hint: "Contrôleur", Difficulty: "medium", Language: "fr"},
+		{Word: "SPIEL", Hint: "Spiel", Difficulty: "easy", Language: "de"},
+	}, game.SystemEditor)
+	if err != nil {
+		t.Fatalf("ImportWords: %v", err)
+	}
+	if err := game.RefreshWordCatalog(store); err != nil {
+		t.Fatalf("RefreshWordCatalog: %v", err)
+	}
+
+	tests := []struct {
+		language, difficulty string
+		wantWord, wantLang   string
+	}{
+		{"fr", "medium", "MANETTE", "fr"},
+		{"fr", "hard", "MANETTE", "fr"},
+		{"de", "easy", "SPIEL", "de"},
+		{"de", "hard", "CARTRIDGE", "en"},
+		{"de", "medium", "JOYSTICK", "en"},
+		{"", "easy", "PIXEL", "en"},
+	}
+	for _, tt := range tests {
+		g, err := game.NewGameWithOptions(store, game.GameOptions{Language: tt.language, Difficulty: tt.difficulty})
+		if err != nil {
+			t.Fatalf("NewGameWithOptions(%s, %s): %v", tt.language, tt.difficulty, err)
+		}
+		if g.Word != tt.wantWord || g.Language != tt.wantLang {
+			t.Errorf("%s %s game: %s in %q, want %s in %q", tt.language, tt.difficulty, g.Word, g.Language, tt.wantWord, tt.wantLang)
+		}
+		if entry := game.NewLeaderboardEntry(g, "alice", "alice"); entry.Language != tt.wantLang {
+			t.Errorf("%s %s game ranked in %q, want %q", tt.language, tt.difficulty, entry.Language, tt.wantLang)
+		}
+	}
+
+	if _, err := game.NewGameWithOptions(store, game.GameOptions{Language: "es"}); !errors.Is(err, game.ErrUnsupportedLanguage) {
+		t.Errorf("NewGameWithOptions(es) = %v, want %v", err, game.ErrUnsupportedLanguage)
+	}
+}
+
+// TestMatchLanguage vérifie le choix de la langue d'après Accept-Language
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"fr-CA,fr;q=0.9,en;q=0.8", "fr"},
+		{"de-DE,fr;q=0.5", "fr"},
+		{"en-GB", "en"},
+		{"ja", game.DefaultLanguage},
+		{"", game.DefaultLanguage},
+		{"not a language;;", game.DefaultLanguage},
+	}
+	for _, tt := range tests {
+		if got := game.MatchLanguage(tt.acceptLanguage); got != tt.want {
+			t.Errorf("MatchLanguage(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Modes de comparaison des lettres proposées avec celles du mot
+const (
+	// MatchFold ignore les accents : E révèle É, È, Ê et Ë (et inversement)
+	MatchFold = "fold"
+	// MatchStrict n'accepte que la lettre exacte : E ne révèle pas É
+	MatchStrict = "strict"
+)
+
+// DefaultMatching est le mode de comparaison des nouvelles parties, modifiable au démarrage
+var DefaultMatching = MatchFold
+
+// ValidMatching indique si mode est un mode de comparaison connu
+func ValidMatching(mode string) bool {
+	return mode == MatchFold || mode == MatchStrict
+}
+
+// autoRevealed indique si un caractère du mot est affiché d'office : espaces,
+// traits d'union, apostrophes et autres caractères qui ne sont ni lettres ni chiffres
+func autoRevealed(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// letterKey retourne la forme sous laquelle une lettre est comparée selon le mode
+func letterKey(r rune, mode string) rune {
+	r = unicode.ToUpper(r)
+	if mode != MatchStrict {
+		r = foldRune(r)
+	}
+	return r
+}
+
+// foldRune retire les accents d'une lettre (É -> E, Ç -> C). Les lettres sans
+// décomposition canonique (Œ, Æ, ß...) sont gardées telles quelles.
+func foldRune(r rune) rune {
+	for _, base := range norm.NFD.String(string(r)) {
+		return base
+	}
+	return r
+}
+
+// normalizeLetter normalise une lettre proposée : composée (NFC) pour qu'un
+// accent saisi séparément soit rattaché à sa lettre, puis en majuscule. Retourne
+// false si ce n'est pas une lettre unique : un chiffre, un trait d'union ou une
+// apostrophe ne sont jamais à deviner.
+func normalizeLetter(letter string) (string, bool) {
+	runes := []rune(norm.NFC.String(strings.TrimSpace(letter)))
+	if len(runes) != 1 || autoRevealed(runes[0]) || !unicode.IsLetter(runes[0]) {
+		return "", false
+	}
+	return string(unicode.ToUpper(runes[0])), true
+}
+
+// ValidLetter indique si une proposition est une lettre unique une fois normalisée
+func ValidLetter(letter string) bool {
+	_, ok := normalizeLetter(letter)
+	return ok
+}
+
+// sanitizeWord normalise un mot ou une phrase proposé (NFC, majuscules, espaces uniques)
+func sanitizeWord(word string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(norm.NFC.String(word)), " "))
+}
+
+// foldWord applique letterKey à chaque lettre d'un mot pour le comparer selon le mode
+func foldWord(word string, mode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '’' {
+			return '\''
+		}
+		return letterKey(r, mode)
+	}, word)
+}
@@ -0,0 +1,71 @@
+package game
+
+import "sync"
+
+// gameHub diffuse l'état des parties modifiées aux abonnés de ce processus
+type gameHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan *Game]struct{}
+}
+
+// gameUpdates diffuse les modifications enregistrées par UpdateGame et les
+// suppressions de DeleteGame
+var gameUpdates gameHub
+
+// SubscribeGame s'abonne aux modifications d'une partie. Le canal reçoit l'état
+// après chaque modification ; un abonné trop lent ne reçoit que le plus récent.
+// Il est fermé quand la partie est supprimée. cancel met fin à l'abonnement.
+func SubscribeGame(id string) (updates <-chan *Game, cancel func()) {
+	ch := make(chan *Game, 1)
+
+	gameUpdates.mu.Lock()
+	if gameUpdates.subscribers == nil {
+		gameUpdates.subscribers = make(map[string]map[chan *Game]struct{})
+	}
+	if gameUpdates.subscribers[id] == nil {
+		gameUpdates.subscribers[id] = make(map[chan *Game]struct{})
+	}
+	gameUpdates.subscribers[id][ch] = struct{}{}
+	gameUpdates.mu.Unlock()
+
+	return ch, func() {
+		gameUpdates.mu.Lock()
+		defer gameUpdates.mu.Unlock()
+		// Déjà fermé si la partie a été supprimée
+		if _, exists := gameUpdates.subscribers[id][ch]; !exists {
+			return
+		}
+		delete(gameUpdates.subscribers[id], ch)
+		if len(gameUpdates.subscribers[id]) == 0 {
+			delete(gameUpdates.subscribers, id)
+		}
+		close(ch)
+	}
+}
+
+// publish envoie l'état d'une partie à ses abonnés sans jamais bloquer
+func (h *gameHub) publish(g *Game) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[g.ID] {
+		state := *g
+		// Remplacer l'état pas encore lu par le plus récent
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- &state
+	}
+}
+
+// closeGame ferme les abonnements à une partie supprimée
+func (h *gameHub) closeGame(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[id] {
+		close(ch)
+	}
+	delete(h.subscribers, id)
+}
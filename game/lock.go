@@ -0,0 +1,47 @@
+package game
+
+import "sync"
+
+// keyedMutex fournit un verrou par clé. Les verrous inutilisés sont libérés
+// pour que la table ne grossisse pas avec le nombre de parties jouées.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refCountedMutex
+}
+
+// refCountedMutex compte les goroutines qui détiennent ou attendent le verrou
+type refCountedMutex struct {
+	sync.Mutex
+	refs int
+}
+
+// lock prend le verrou associé à key et retourne la fonction qui le relâche
+func (k *keyedMutex) lock(key string) (unlock func()) {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*refCountedMutex)
+	}
+	m, exists := k.locks[key]
+	if !exists {
+		m = &refCountedMutex{}
+		k.locks[key] = m
+	}
+	m.refs++
+	k.mu.Unlock()
+
+	m.Lock()
+
+	return func() {
+		m.Unlock()
+
+		k.mu.Lock()
+		m.refs--
+		if m.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// gameLocks sérialise les modifications d'une même partie dans ce processus
+var gameLocks keyedMutex
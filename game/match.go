@@ -0,0 +1,500 @@
+package game
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Erreurs liées aux courses multijoueurs
+var (
+	ErrMatchNotFound         = errors.New("match not found")
+	ErrMatchStarted          = errors.New("match has already started")
+	ErrMatchFull             = errors.New("match is full")
+	ErrMatchNotEnoughPlayers = errors.New("not enough players to start the match")
+	ErrNotMatchHost          = errors.New("only the host can start the match")
+	ErrNotMatchPlayer        = errors.New("player is not in this match")
+	ErrMatchNotStarted       = errors.New("match has not started yet")
+	ErrMatchOver             = errors.New("match is over")
+	ErrMatchGame             = errors.New("match games are played through their match")
+	ErrMatchConflict         = errors.New("match was modified concurrently")
+)
+
+// Nombre de joueurs d'une course
+const (
+	MinMatchPlayers = 2
+	MaxMatchPlayers = 8
+)
+
+// MatchStartDelay est le compte à rebours entre le lancement d'une course et
+// les premières propositions, modifiable au démarrage
+var MatchStartDelay = 5 * time.Second
+
+// Statuts d'une course. MatchCountdown n'est pas enregistré : c'est une course
+// MatchRunning dont le départ n'est pas encore donné (voir StatusAt).
+const (
+	MatchLobby     = "lobby"
+	MatchCountdown = "countdown"
+	MatchRunning   = "in_progress"
+	MatchFinished  = "finished"
+	MatchCancelled = "cancelled" // l'hôte a quitté le salon
+)
+
+// Match est une course entre plusieurs joueurs sur un même mot. Chacun joue sa
+// propre partie ; le premier à trouver le mot gagne et arrête les autres.
+type Match struct {
+	ID         string        `json:"id"`
+	Code       string        `json:"code"` // code court à communiquer pour rejoindre le salon
+	HostID     string        `json:"host_id"`
+	Status     string        `json:"status"`
+	Difficulty string        `json:"difficulty"`
+	Language   string        `json:"language"`
+	Category   string        `json:"category,omitempty"`
+	Matching   string        `json:"matching"`
+	Word       string        `json:"-"`
+	Hint       string        `json:"-"`
+	Seed       int64         `json:"-"`       // graine du tirage du mot, pour rejouer la course
+	Players    []MatchPlayer `json:"players"` // dans l'ordre d'arrivée
+	WinnerID   string        `json:"winner_id,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	StartsAt   time.Time     `json:"starts_at,omitzero"`
+	FinishedAt time.Time     `json:"finished_at,omitzero"`
+	Version    int           `json:"-"` // incrémentée à chaque enregistrement, voir MatchStore.SaveMatch
+}
+
+// MatchPlayer est un joueur d'une course
+type MatchPlayer struct {
+	PlayerID   string    `json:"player_id"`
+	PlayerName string    `json:"player_name"`
+	GameID     string    `json:"-"` // partie du joueur, créée au lancement
+	JoinedAt   time.Time `json:"joined_at"`
+	Rank       int       `json:"rank,omitempty"` // classement final, une fois la course terminée
+}
+
+// MatchOptions regroupe les paramètres de création d'une course
+type MatchOptions struct {
+	HostID     string
+	HostName   string
+	Difficulty string // "medium" si vide
+	Language   string // DefaultLanguage si vide
+	Category   string // toutes si vide
+	Matching   string // DefaultMatching si vide
+	Seed       *int64 // graine du tirage du mot (tirée de la source aléatoire si nil)
+}
+
+// MatchGuess est une proposition dans une course : une lettre ou le mot entier
+type MatchGuess struct {
+	Letter string
+	Word   string
+}
+
+// MatchProgress résume l'avancement d'un joueur sans dévoiler ses lettres
+type MatchProgress struct {
+	Revealed     int    `json:"revealed"` // lettres trouvées
+	Letters      int    `json:"letters"`  // lettres à trouver en tout
+	WrongGuesses int    `json:"wrong_guesses"`
+	Remaining    int    `json:"remaining"`
+	Status       string `json:"status"`
+}
+
+// MatchStore décrit le stockage des courses
+type MatchStore interface {
+	// CreateMatch enregistre une course (utils.ErrDuplicateID si son ID ou son
+	// code est déjà utilisé)
+	CreateMatch(m *Match) error
+	// GetMatch récupère une course par son ID (ErrMatchNotFound si absente)
+	GetMatch(id string) (*Match, error)
+	// GetMatchByCode récupère une course par son code (ErrMatchNotFound si absente)
+	GetMatchByCode(code string) (*Match, error)
+	// SaveMatch met à jour une course et ses joueurs si sa version est toujours
+	// celle lue, puis incrémente m.Version (ErrMatchNotFound si absente,
+	// ErrMatchConflict si elle a été enregistrée entre-temps)
+	SaveMatch(m *Match) error
+}
+
+// matchLocks sérialise les modifications d'une même course dans ce processus
+var matchLocks keyedMutex
+
+// StatusAt retourne le statut de la course à l'instant now
+func (m *Match) StatusAt(now time.Time) string {
+	if m.Status == MatchRunning && now.Before(m.StartsAt) {
+		return MatchCountdown
+	}
+	return m.Status
+}
+
+// Player retourne le joueur de la course, nil s'il n'en fait pas partie
+func (m *Match) Player(playerID string) *MatchPlayer {
+	for i := range m.Players {
+		if m.Players[i].PlayerID == playerID {
+			return &m.Players[i]
+		}
+	}
+	return nil
+}
+
+// Progress résume l'avancement d'une partie
+func Progress(g *Game) MatchProgress {
+	letters := 0
+	for _, char := range g.Word {
+		if !autoRevealed(char) {
+			letters++
+		}
+	}
+	return MatchProgress{
+		Revealed:     letters - g.hiddenLetters(),
+		Letters:      letters,
+		WrongGuesses: g.WrongGuesses(),
+		Remaining:    g.Remaining,
+		Status:       g.Status,
+	}
+}
+
+// NewMatch ouvre le salon d'une course dont l'hôte est le premier joueur. Le
+// mot est tiré dès maintenant (ErrUnsupportedLanguage, ErrUnknownCategory).
+func NewMatch(store MatchStore, opts MatchOptions) (*Match, error) {
+	difficulty := opts.Difficulty
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	matching := opts.Matching
+	if matching == "" {
+		matching = DefaultMatching
+	}
+	language := opts.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+	if !SupportedLanguage(language) {
+		return nil, ErrUnsupportedLanguage
+	}
+
+	// Comme pour une partie, la même graine tire le même mot
+	seed := randomSource.Int63()
+	if opts.Seed != nil {
+		seed = *opts.Seed
+	}
+	selection, err := SelectWord(NewSeededSource(seed), language, difficulty, opts.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	m := &Match{
+		HostID:     opts.HostID,
+		Status:     MatchLobby,
+		Difficulty: difficulty,
+		Language:   selection.Language,
+		Category:   opts.Category,
+		Matching:   matching,
+		Word:       selection.Word,
+		Hint:       selection.Hint,
+		Seed:       seed,
+		Players:    []MatchPlayer{{PlayerID: opts.HostID, PlayerName: opts.HostName, JoinedAt: now}},
+		CreatedAt:  now,
+	}
+
+	err = utils.RetryOnDuplicateID(func() error {
+		m.ID = utils.NewID()
+		m.Code = utils.GenerateShortCode()
+		return store.CreateMatch(m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetMatch récupère une course par son ID
+func GetMatch(store MatchStore, id string) (*Match, error) {
+	return store.GetMatch(id)
+}
+
+// UpdateMatch charge une course, lui applique fn puis l'enregistre. Les appels
+// concurrents sur une même course sont sérialisés dans ce processus ; si un
+// autre serveur l'a enregistrée entre-temps, elle est relue et fn réappliqué
+// (ErrMatchConflict après maxUpdateAttempts essais). fn doit donc pouvoir être
+// rappelée. Si fn renvoie une erreur, rien n'est enregistré.
+func UpdateMatch(store MatchStore, id string, fn func(*Match) error) (*Match, error) {
+	unlock := matchLocks.lock(id)
+	defer unlock()
+
+	for range maxUpdateAttempts {
+		m, err := store.GetMatch(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fn(m); err != nil {
+			return nil, err
+		}
+
+		err = store.SaveMatch(m)
+		if errors.Is(err, ErrMatchConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	return nil, ErrMatchConflict
+}
+
+// JoinMatch ajoute un joueur au salon de la course portant ce code. Rejoindre
+// une course dont on fait déjà partie la retourne telle quelle.
+func JoinMatch(store MatchStore, code, playerID, playerName string) (*Match, error) {
+	m, err := store.GetMatchByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateMatch(store, m.ID, func(m *Match) error {
+		if m.Player(playerID) != nil {
+			return nil
+		}
+		if m.Status != MatchLobby {
+			return ErrMatchStarted
+		}
+		if len(m.Players) >= MaxMatchPlayers {
+			return ErrMatchFull
+		}
+
+		m.Players = append(m.Players, MatchPlayer{
+			PlayerID: playerID, PlayerName: playerName, JoinedAt: time.Now().UTC(),
+		})
+		return nil
+	})
+}
+
+// LeaveMatch retire un joueur du salon ; le départ de l'hôte annule la course.
+// Pendant la course, quitter revient à abandonner : la partie du joueur est
+// perdue et la course se termine si plus personne ne joue.
+func LeaveMatch(games GameStore, store MatchStore, id, playerID string) (*Match, error) {
+	// La partie reste abandonnée si la course doit être relue
+	abandoned := false
+	return UpdateMatch(store, id, func(m *Match) error {
+		player := m.Player(playerID)
+		if player == nil {
+			return ErrNotMatchPlayer
+		}
+
+		switch m.Status {
+		case MatchLobby:
+			if playerID == m.HostID {
+				m.Status = MatchCancelled
+				m.FinishedAt = time.Now().UTC()
+				return nil
+			}
+			m.Players = removePlayer(m.Players, playerID)
+			return nil
+		case MatchRunning:
+			if !abandoned {
+				_, err := UpdateGame(games, player.GameID, func(g *Game) error {
+					if g.Status != "in_progress" {
+						return ErrGameOver
+					}
+					g.Status = "lost"
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				abandoned = true
+			}
+			return finishMatchIfOver(games, m)
+		default:
+			return ErrMatchOver
+		}
+	})
+}
+
+// removePlayer retire un joueur de la liste en gardant l'ordre d'arrivée
+func removePlayer(players []MatchPlayer, playerID string) []MatchPlayer {
+	kept := players[:0]
+	for _, p := range players {
+		if p.PlayerID != playerID {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// StartMatch lance une course depuis son salon : chaque joueur reçoit sa partie
+// sur le mot de la course, jouable après MatchStartDelay. Seul l'hôte peut la
+// lancer, avec au moins MinMatchPlayers joueurs.
+func StartMatch(games GameStore, store MatchStore, id, playerID string) (*Match, error) {
+	// Parties créées par le dernier essai, jamais enregistrées dans la course
+	// s'il échoue ou doit être relu
+	var created []string
+	m, err := UpdateMatch(store, id, func(m *Match) error {
+		discardGames(games, created)
+		created = nil
+
+		if m.HostID != playerID {
+			return ErrNotMatchHost
+		}
+		if m.Status != MatchLobby {
+			return ErrMatchStarted
+		}
+		if len(m.Players) < MinMatchPlayers {
+			return ErrMatchNotEnoughPlayers
+		}
+
+		for i := range m.Players {
+			g, err := NewGameWithOptions(games, GameOptions{
+				Difficulty: m.Difficulty,
+				PlayerID:   m.Players[i].PlayerID,
+				Matching:   m.Matching,
+				Language:   m.Language,
+				Category:   m.Category,
+				Word:       &WordSelection{Word: m.Word, Hint: m.Hint},
+				Match:      m.ID,
+			})
+			if err != nil {
+				return err
+			}
+			created = append(created, g.ID)
+			m.Players[i].GameID = g.ID
+		}
+
+		m.Status = MatchRunning
+		m.StartsAt = time.Now().UTC().Add(MatchStartDelay)
+		return nil
+	})
+	if err != nil {
+		discardGames(games, created)
+		return nil, err
+	}
+	return m, nil
+}
+
+// discardGames supprime des parties de course qu'aucune course ne référence ;
+// un échec est seulement journalisé
+func discardGames(games GameStore, ids []string) {
+	for _, id := range ids {
+		if err := games.DeleteGame(id); err != nil && !errors.Is(err, ErrGameNotFound) {
+			log.Printf("discarding match game %s: %v", id, err)
+		}
+	}
+}
+
+// SubmitMatchGuess applique la proposition d'un joueur à sa partie et retourne
+// la course, la partie mise à jour et si la proposition est juste. Le premier
+// joueur à trouver le mot gagne la course ; elle se termine aussi quand tous les
+// joueurs ont perdu.
+func SubmitMatchGuess(games GameStore, store MatchStore, id, playerID string, guess MatchGuess) (*Match, *Game, bool, error) {
+	if guess.Word == "" && !ValidLetter(guess.Letter) {
+		return nil, nil, false, ErrInvalidLetter
+	}
+
+	var g *Game
+	var found bool
+	// La proposition n'est appliquée qu'une fois, même si la course est relue
+	applied := false
+	m, err := UpdateMatch(store, id, func(m *Match) error {
+		switch m.StatusAt(time.Now()) {
+		case MatchLobby, MatchCountdown:
+			return ErrMatchNotStarted
+		case MatchFinished, MatchCancelled:
+			return ErrMatchOver
+		}
+		player := m.Player(playerID)
+		if player == nil {
+			return ErrNotMatchPlayer
+		}
+
+		if !applied {
+			var err error
+			g, err = UpdateGame(games, player.GameID, func(g *Game) error {
+				if g.Status != "in_progress" {
+					return ErrGameOver
+				}
+				if guess.Word != "" {
+					found = g.GuessWord(guess.Word)
+				} else {
+					found = g.MakeGuess(guess.Letter)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			applied = true
+		}
+
+		if g.Status == "won" && m.WinnerID == "" {
+			m.WinnerID = playerID
+		}
+		return finishMatchIfOver(games, m)
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return m, g, found, nil
+}
+
+// finishMatchIfOver termine la course si elle a un vainqueur, dont la victoire
+// arrête les parties des autres joueurs, ou si plus aucune partie n'est en
+// cours, puis enregistre le classement final
+func finishMatchIfOver(games GameStore, m *Match) error {
+	playerGames := make(map[string]*Game, len(m.Players))
+	inProgress := 0
+	for _, p := range m.Players {
+		g, err := games.GetGame(p.GameID)
+		if err != nil {
+			return err
+		}
+		playerGames[p.PlayerID] = g
+		if !g.IsOver() {
+			inProgress++
+		}
+	}
+	if m.WinnerID == "" && inProgress > 0 {
+		return nil
+	}
+
+	for playerID, g := range playerGames {
+		if g.IsOver() {
+			continue
+		}
+		stopped, err := UpdateGame(games, g.ID, func(g *Game) error {
+			if g.Status == "in_progress" {
+				g.Status = "lost"
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		playerGames[playerID] = stopped
+	}
+
+	// Le vainqueur d'abord, puis les autres selon leur avancement
+	order := make([]int, len(m.Players))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := m.Players[order[i]], m.Players[order[j]]
+		if (a.PlayerID == m.WinnerID) != (b.PlayerID == m.WinnerID) {
+			return a.PlayerID == m.WinnerID
+		}
+		pa, pb := Progress(playerGames[a.PlayerID]), Progress(playerGames[b.PlayerID])
+		if pa.Revealed != pb.Revealed {
+			return pa.Revealed > pb.Revealed
+		}
+		return pa.WrongGuesses < pb.WrongGuesses
+	})
+	for rank, i := range order {
+		m.Players[i].Rank = rank + 1
+	}
+
+	m.Status = MatchFinished
+	m.FinishedAt = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,139 @@
+package game_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// TestMatchSeed vérifie que le mot d'une course est tiré de sa graine : deux
+// courses de même graine ont le même mot
+func TestMatchSeed(t *testing.T) {
+	store := memory.New()
+	seed := int64(7)
+	var words []string
+	for range 2 {
+		m, err := game.NewMatch(store, game.MatchOptions{HostID: "host", HostName: "host", Difficulty: "hard", Seed: &seed})
+		if err != nil {
+			t.Fatalf("NewMatch: %v", err)
+		}
+		if m.Seed != seed {
+			t.Errorf("match seed = %d, want %d", m.Seed, seed)
+		}
+		words = append(words, m.Word)
+	}
+	if words[0] != words[1] {
+		t.Errorf("matches with the same seed drew %q and %q", words[0], words[1])
+	}
+}
+
+// TestSubmitMatchScore vérifie que le score d'une partie de course ne peut pas
+// être soumis au classement
+func TestSubmitMatchScore(t *testing.T) {
+	store := memory.New()
+	g, err := game.NewGameWithOptions(store, game.GameOptions{
+		PlayerID: "player",
+		Word:     &game.WordSelection{Word: "AB", Hint: "Letters"},
+		Match:    "match",
+	})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+	g.MakeGuess("A")
+	g.MakeGuess("B")
+
+	_, err = game.SubmitScore(store, g, "player", "player")
+	if !errors.Is(err, game.ErrMatchGame) {
+		t.Errorf("SubmitScore(match game) = %v, want %v", err, game.ErrMatchGame)
+	}
+}
+
+// racingStore simule un autre serveur : avant le prochain enregistrement d'une
+// course, interfere modifie et enregistre sa propre copie de la course
+type racingStore struct {
+	*memory.Store
+	interfere func(m *game.Match)
+	created   []string
+}
+
+func (s *racingStore) SaveMatch(m *game.Match) error {
+	if interfere := s.interfere; interfere != nil {
+		s.interfere = nil
+		other, err := s.Store.GetMatch(m.ID)
+		if err != nil {
+			return err
+		}
+		interfere(other)
+		if err := s.Store.SaveMatch(other); err != nil {
+			return err
+		}
+	}
+	return s.Store.SaveMatch(m)
+}
+
+func (s *racingStore) CreateGame(g *game.Game) error {
+	s.created = append(s.created, g.ID)
+	return s.Store.CreateGame(g)
+}
+
+// newRacingMatch ouvre une course de deux joueurs sur un mot connu
+func newRacingMatch(t *testing.T) (*racingStore, *game.Match) {
+	t.Helper()
+	delay := game.MatchStartDelay
+	game.MatchStartDelay = 0
+	t.Cleanup(func() { game.MatchStartDelay = delay })
+
+	store := &racingStore{Store: memory.New()}
+	m, err := game.NewMatch(store, game.MatchOptions{HostID: "host", HostName: "host"})
+	if err != nil {
+		t.Fatalf("NewMatch: %v", err)
+	}
+	if m, err = game.JoinMatch(store, m.Code, "guest", "guest"); err != nil {
+		t.Fatalf("JoinMatch: %v", err)
+	}
+	return store, m
+}
+
+// TestStartMatchConflict vérifie qu'une course annulée par un autre serveur
+// pendant son lancement n'est pas lancée, et que les parties créées pour elle
+// sont supprimées
+func TestStartMatchConflict(t *testing.T) {
+	store, m := newRacingMatch(t)
+	store.interfere = func(m *game.Match) { m.Status = game.MatchCancelled }
+
+	_, err := game.StartMatch(store, store, m.ID, "host")
+	if !errors.Is(err, game.ErrMatchStarted) {
+		t.Fatalf("StartMatch(cancelled meanwhile) = %v, want %v", err, game.ErrMatchStarted)
+	}
+	if len(store.created) != 2 {
+		t.Fatalf("StartMatch created %d games, want 2", len(store.created))
+	}
+	for _, id := range store.created {
+		if _, err := store.GetGame(id); !errors.Is(err, game.ErrGameNotFound) {
+			t.Errorf("GetGame(%s) = %v, want %v", id, err, game.ErrGameNotFound)
+		}
+	}
+}
+
+// TestMatchGuessConflict vérifie qu'une proposition n'est appliquée qu'une fois
+// quand la course est relue après l'enregistrement d'un autre serveur
+func TestMatchGuessConflict(t *testing.T) {
+	store, m := newRacingMatch(t)
+	if _, err := game.StartMatch(store, store, m.ID, "host"); err != nil {
+		t.Fatalf("StartMatch: %v", err)
+	}
+	store.interfere = func(*game.Match) {}
+
+	m, g, _, err := game.SubmitMatchGuess(store, store, m.ID, "guest", game.MatchGuess{Word: m.Word})
+	if err != nil {
+		t.Fatalf("SubmitMatchGuess: %v", err)
+	}
+	if g.Status != "won" || len(g.WordGuesses) != 1 {
+		t.Errorf("guest game is %s with %d word guesses, want won with 1", g.Status, len(g.WordGuesses))
+	}
+	if m.Status != game.MatchFinished || m.WinnerID != "guest" {
+		t.Errorf("match is %s won by %q, want finished won by guest", m.Status, m.WinnerID)
+	}
+}
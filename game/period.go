@@ -0,0 +1,49 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+// Périodes du classement
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+	PeriodAllTime = "all-time"
+)
+
+// ErrInvalidPeriod est renvoyée pour une période de classement inconnue
+var ErrInvalidPeriod = errors.New("invalid leaderboard period")
+
+// LeaderboardLocation est le fuseau horaire qui délimite les jours, semaines
+// et mois du classement, modifiable au démarrage
+var LeaderboardLocation = time.UTC
+
+// PeriodRange retourne l'intervalle [from, to) de la période contenant now.
+// Les semaines commencent le lundi. Pour "all-time" (ou une période vide),
+// les deux bornes sont nulles.
+func PeriodRange(period string, now time.Time) (from, to time.Time, err error) {
+	now = now.In(LeaderboardLocation)
+	year, month, day := now.Date()
+
+	switch period {
+	case "", PeriodAllTime:
+		return time.Time{}, time.Time{}, nil
+	case PeriodDaily:
+		from = time.Date(year, month, day, 0, 0, 0, 0, LeaderboardLocation)
+		to = from.AddDate(0, 0, 1)
+	case PeriodWeekly:
+		// time.Weekday commence le dimanche (0) : décaler pour commencer le lundi
+		offset := (int(now.Weekday()) + 6) % 7
+		from = time.Date(year, month, day-offset, 0, 0, 0, 0, LeaderboardLocation)
+		to = from.AddDate(0, 0, 7)
+	case PeriodMonthly:
+		from = time.Date(year, month, 1, 0, 0, 0, 0, LeaderboardLocation)
+		to = from.AddDate(0, 1, 0)
+	default:
+		return time.Time{}, time.Time{}, ErrInvalidPeriod
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,54 @@
+package game_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+)
+
+// TestPeriodRange vérifie les bornes des périodes du classement, délimitées
+// dans le fuseau LeaderboardLocation
+func TestPeriodRange(t *testing.T) {
+	zone := time.FixedZone("UTC+10", 10*60*60)
+	previous := game.LeaderboardLocation
+	game.LeaderboardLocation = zone
+	t.Cleanup(func() { game.LeaderboardLocation = previous })
+
+	local := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, zone)
+	}
+	tests := []struct {
+		name     string
+		period   string
+		now      time.Time
+		from, to time.Time
+	}{
+		// 23h30 UTC le 18 est déjà le 19 dans le fuseau du classement
+		{"daily", game.PeriodDaily, time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC), local(2026, 10, 19, 0), local(2026, 10, 20, 0)},
+		{"daily at midnight", game.PeriodDaily, local(2026, 10, 19, 0), local(2026, 10, 19, 0), local(2026, 10, 20, 0)},
+		{"weekly on sunday", game.PeriodWeekly, local(2026, 10, 18, 22), local(2026, 10, 12, 0), local(2026, 10, 19, 0)},
+		{"weekly on monday", game.PeriodWeekly, local(2026, 10, 19, 0), local(2026, 10, 19, 0), local(2026, 10, 26, 0)},
+		{"weekly across months", game.PeriodWeekly, local(2026, 11, 1, 12), local(2026, 10, 26, 0), local(2026, 11, 2, 0)},
+		{"monthly", game.PeriodMonthly, local(2026, 10, 31, 23), local(2026, 10, 1, 0), local(2026, 11, 1, 0)},
+		{"monthly across years", game.PeriodMonthly, local(2026, 12, 15, 8), local(2026, 12, 1, 0), local(2027, 1, 1, 0)},
+		{"all-time", game.PeriodAllTime, local(2026, 10, 18, 12), time.Time{}, time.Time{}},
+		{"empty", "", local(2026, 10, 18, 12), time.Time{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := game.PeriodRange(tt.period, tt.now)
+			if err != nil {
+				t.Fatalf("PeriodRange: %v", err)
+			}
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("PeriodRange(%q, %v) = [%v, %v), want [%v, %v)", tt.period, tt.now, from, to, tt.from, tt.to)
+			}
+		})
+	}
+
+	if _, _, err := game.PeriodRange("yearly", time.Now()); !errors.Is(err, game.ErrInvalidPeriod) {
+		t.Errorf("PeriodRange(yearly) = %v, want %v", err, game.ErrInvalidPeriod)
+	}
+}
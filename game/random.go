@@ -0,0 +1,50 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RandomSource fournit les tirages aléatoires des parties
+type RandomSource interface {
+	// Int63 retourne un entier positif sur 63 bits
+	Int63() int64
+	// Intn retourne un entier dans [0, n)
+	Intn(n int) int
+}
+
+// Source utilisée pour tirer la graine de chaque partie, remplaçable au
+// démarrage ou dans les tests
+var randomSource RandomSource = NewSeededSource(time.Now().UnixNano())
+
+// SetRandomSource remplace la source aléatoire des parties
+func SetRandomSource(s RandomSource) {
+	randomSource = s
+}
+
+// SeededSource est une source reproductible : une même graine donne toujours
+// la même suite de tirages. Elle peut être partagée entre goroutines.
+type SeededSource struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewSeededSource crée une source aléatoire à partir d'une graine
+func NewSeededSource(seed int64) *SeededSource {
+	return &SeededSource{r: rand.New(rand.NewSource(seed))}
+}
+
+// Int63 retourne un entier positif sur 63 bits
+func (s *SeededSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Int63()
+}
+
+// Intn retourne un entier dans [0, n)
+func (s *SeededSource) Intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Intn(n)
+}
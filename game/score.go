@@ -2,43 +2,106 @@ package game
 
 import (
 	"strings"
-	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/N95Ryan/8bit-hangman-back/utils"
 )
 
 // Structure pour stocker les scores
 type LeaderboardEntry struct {
-	PlayerID          string `json:"player_id"`
-	PlayerName        string `json:"player_name"`
-	Score             int    `json:"score"`
-	WordLength        int    `json:"word_length"`
-	RemainingAttempts int    `json:"remaining_attempts"`
-	Difficulty        string `json:"difficulty"`
-}
-
-// Stockage en mémoire des scores
-var (
-	leaderboard      = []LeaderboardEntry{}
-	leaderboardMutex sync.RWMutex
-)
+	ID                string    `json:"id"`
+	GameID            string    `json:"game_id"`
+	PlayerID          string    `json:"player_id"`
+	PlayerName        string    `json:"player_name"`
+	Score             int       `json:"score"`
+	WordLength        int       `json:"word_length"`
+	RemainingAttempts int       `json:"remaining_attempts"`
+	WrongGuesses      int       `json:"wrong_guesses"`
+	Difficulty        string    `json:"difficulty"`
+	Language          string    `json:"language"`
+	Category          string    `json:"category,omitempty"` // catégorie de la partie (vide = toutes)
+	Daily             string    `json:"daily,omitempty"`    // jour du défi quotidien joué, vide sinon
+	SubmittedAt       time.Time `json:"submitted_at"`
+}
+
+// RanksBefore indique si e est classée avant other : score décroissant, puis
+// moins d'erreurs, puis soumission la plus ancienne, puis ID pour départager
+func (e LeaderboardEntry) RanksBefore(other LeaderboardEntry) bool {
+	if e.Score != other.Score {
+		return e.Score > other.Score
+	}
+	if e.WrongGuesses != other.WrongGuesses {
+		return e.WrongGuesses < other.WrongGuesses
+	}
+	if !e.SubmittedAt.Equal(other.SubmittedAt) {
+		return e.SubmittedAt.Before(other.SubmittedAt)
+	}
+	return e.ID < other.ID
+}
+
+// MaxLeaderboardLimit borne la taille d'une page du classement
+const MaxLeaderboardLimit = 100
+
+// LeaderboardQuery sélectionne une page du classement
+type LeaderboardQuery struct {
+	Difficulty    string    // vide pour toutes les difficultés
+	Language      string    // vide pour toutes les langues
+	Category      string    // vide pour toutes les catégories, y compris les parties sans catégorie
+	Daily         string    // jour d'un défi quotidien : seuls les scores de ses parties
+	Period        string    // "daily", "weekly", "monthly" ou "all-time", remplace From et To
+	From          time.Time // scores soumis à partir de From (zéro = sans limite)
+	To            time.Time // scores soumis avant To (zéro = sans limite)
+	BestPerPlayer bool      // ne garde que la meilleure entrée de chaque joueur
+	Offset        int
+	Limit         int
+}
+
+// RankedEntry est une entrée du classement accompagnée de son rang (à partir de 1)
+type RankedEntry struct {
+	Rank int `json:"rank"`
+	LeaderboardEntry
+}
+
+// LeaderboardPage est une page du classement
+type LeaderboardPage struct {
+	Entries []RankedEntry `json:"entries"`
+	Total   int           `json:"total"` // nombre total d'entrées correspondant à la requête
+	Offset  int           `json:"offset"`
+	Limit   int           `json:"limit"`
+	Period  string        `json:"period,omitempty"`
+	From    *time.Time    `json:"from,omitempty"`
+	To      *time.Time    `json:"to,omitempty"`
+}
+
+// letterFrequency associe à chaque lettre un multiplicateur de rareté
+var letterFrequency = map[rune]int{
+	'E': 1, 'A': 1, 'I': 1, 'N': 1, 'O': 1, 'R': 1, 'S': 1, 'T': 1,
+	'U': 2, 'L': 2, 'D': 2, 'M': 2,
+	'G': 3, 'B': 3, 'C': 3, 'P': 3,
+	'F': 4, 'H': 4, 'V': 4,
+	'J': 5, 'Q': 5, 'K': 5, 'W': 5, 'X': 5, 'Y': 5, 'Z': 5,
+}
 
 // CalculateScore calcule le score pour une lettre correcte
 func CalculateScore(word string, letter string) int {
+	return CalculateLetterScore(letter, strings.Count(word, letter))
+}
+
+// CalculateLetterScore calcule le score d'une lettre révélant occurrences
+// lettres du mot. Une lettre accentuée vaut sa lettre de base (É comme E).
+func CalculateLetterScore(letter string, occurrences int) int {
 	// Points de base pour chaque occurrence de la lettre
 	basePoints := 10
-	occurrences := strings.Count(word, letter)
 
 	// Bonus pour les lettres rares
-	letterFrequency := map[string]int{
-		"E": 1, "A": 1, "I": 1, "N": 1, "O": 1, "R": 1, "S": 1, "T": 1,
-		"U": 2, "L": 2, "D": 2, "M": 2,
-		"G": 3, "B": 3, "C": 3, "P": 3,
-		"F": 4, "H": 4, "V": 4,
-		"J": 5, "Q": 5, "K": 5, "W": 5, "X": 5, "Y": 5, "Z": 5,
-	}
-
-	rarityMultiplier := letterFrequency[letter]
-	if rarityMultiplier == 0 {
-		rarityMultiplier = 1
+	rarityMultiplier := 1
+	for _, r := range letter {
+		if m := letterFrequency[foldRune(unicode.ToUpper(r))]; m > 0 {
+			rarityMultiplier = m
+		}
+		break
 	}
 
 	return basePoints * occurrences * rarityMultiplier
@@ -50,61 +113,123 @@ func CalculateBonusScore(remainingAttempts int) int {
 	return remainingAttempts * 50
 }
 
-// AddToLeaderboard ajoute un score au classement
-func AddToLeaderboard(playerID string, playerName string, score int, wordLength int, remainingAttempts int, difficulty string) {
-	entry := LeaderboardEntry{
+// CalculateWordGuessBonus calcule le bonus d'un mot entier trouvé : plus il
+// restait de lettres cachées, plus le pari était risqué
+func CalculateWordGuessBonus(hiddenLetters int) int {
+	return hiddenLetters * 30
+}
+
+// NewLeaderboardEntry prépare l'entrée de classement d'une partie terminée
+func NewLeaderboardEntry(g *Game, playerID string, playerName string) LeaderboardEntry {
+	return LeaderboardEntry{
+		GameID:            g.ID,
 		PlayerID:          playerID,
 		PlayerName:        playerName,
-		Score:             score,
-		WordLength:        wordLength,
-		RemainingAttempts: remainingAttempts,
-		Difficulty:        difficulty,
+		Score:             g.Score,
+		WordLength:        utf8.RuneCountInString(g.Word),
+		RemainingAttempts: g.Remaining,
+		WrongGuesses:      g.WrongGuesses(),
+		Difficulty:        g.Difficulty,
+		Language:          g.Language,
+		Category:          g.Category,
+		Daily:             g.Daily,
 	}
-
-	leaderboardMutex.Lock()
-	defer leaderboardMutex.Unlock()
-
-	leaderboard = append(leaderboard, entry)
 }
 
-// GetLeaderboard retourne le classement des meilleurs scores
-func GetLeaderboard(limit int) []LeaderboardEntry {
-	leaderboardMutex.RLock()
-	defer leaderboardMutex.RUnlock()
-
-	// Copier le classement pour éviter les modifications concurrentes
-	result := make([]LeaderboardEntry, len(leaderboard))
-	copy(result, leaderboard)
+// AddToLeaderboard ajoute un score au classement en lui attribuant un ID et
+// sa date de soumission. S'il entre dans le haut du classement de sa
+// difficulté, le changement est diffusé aux abonnés de SubscribeLeaderboard.
+func AddToLeaderboard(store LeaderboardStore, entry LeaderboardEntry) (*LeaderboardEntry, error) {
+	return addToLeaderboard(store, entry, store.AddLeaderboardEntry)
+}
 
-	// Trier par score (à implémenter avec sort.Slice si nécessaire)
+// addToLeaderboard enregistre entry avec insert puis diffuse le changement
+func addToLeaderboard(store LeaderboardStore, entry LeaderboardEntry, insert func(LeaderboardEntry) error) (*LeaderboardEntry, error) {
+	entry.SubmittedAt = time.Now().UTC()
+	err := utils.RetryOnDuplicateID(func() error {
+		entry.ID = utils.NewID()
+		return insert(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	publishLeaderboardChange(store, entry)
+	return &entry, nil
+}
 
-	// Limiter le nombre de résultats
-	if limit > 0 && limit < len(result) {
-		return result[:limit]
+// SubmitScore inscrit au classement le score d'une partie terminée et le compte
+// dans les statistiques du joueur, de façon atomique. Seul le joueur qui a créé
+// la partie peut le soumettre, et une seule fois (ErrMatchGame pour une partie
+// de course, classée par sa course).
+func SubmitScore(store LeaderboardStore, g *Game, playerID string, playerName string) (*LeaderboardEntry, error) {
+	if !g.IsOver() {
+		return nil, ErrGameInProgress
+	}
+	if g.PlayerID != playerID {
+		return nil, ErrNotGamePlayer
+	}
+	if g.Match != "" {
+		return nil, ErrMatchGame
 	}
 
-	return result
+	won := g.Status == "won"
+	return addToLeaderboard(store, NewLeaderboardEntry(g, playerID, playerName), func(entry LeaderboardEntry) error {
+		return store.SubmitLeaderboardEntry(entry, won)
+	})
 }
 
-// GetLeaderboardByDifficulty retourne le classement filtré par niveau de difficulté
-func GetLeaderboardByDifficulty(difficulty string, limit int) []LeaderboardEntry {
-	leaderboardMutex.RLock()
-	defer leaderboardMutex.RUnlock()
-
-	// Filtrer par difficulté
-	var filteredLeaderboard []LeaderboardEntry
-	for _, entry := range leaderboard {
-		if entry.Difficulty == difficulty {
-			filteredLeaderboard = append(filteredLeaderboard, entry)
+// QueryLeaderboard retourne une page du classement, chaque entrée portant son rang
+// (ErrInvalidPeriod si la période est inconnue)
+func QueryLeaderboard(store LeaderboardStore, query LeaderboardQuery) (*LeaderboardPage, error) {
+	if query.Period != "" {
+		from, to, err := PeriodRange(query.Period, time.Now())
+		if err != nil {
+			return nil, err
 		}
+		query.From, query.To = from, to
 	}
+	return queryLeaderboard(query, store.ListLeaderboardEntries)
+}
 
-	// Trier par score (à implémenter avec sort.Slice si nécessaire)
+// queryLeaderboard normalise la pagination, lit les entrées avec list et les numérote
+func queryLeaderboard(query LeaderboardQuery, list func(LeaderboardQuery) ([]LeaderboardEntry, int, error)) (*LeaderboardPage, error) {
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	if query.Limit <= 0 || query.Limit > MaxLeaderboardLimit {
+		query.Limit = MaxLeaderboardLimit
+	}
 
-	// Limiter le nombre de résultats
-	if limit > 0 && limit < len(filteredLeaderboard) {
-		return filteredLeaderboard[:limit]
+	entries, total, err := list(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LeaderboardPage{
+		Entries: make([]RankedEntry, len(entries)),
+		Total:   total,
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Period:  query.Period,
+	}
+	if !query.From.IsZero() {
+		page.From = &query.From
 	}
+	if !query.To.IsZero() {
+		page.To = &query.To
+	}
+	for i, entry := range entries {
+		page.Entries[i] = RankedEntry{Rank: query.Offset + i + 1, LeaderboardEntry: entry}
+	}
+	return page, nil
+}
+
+// GetLeaderboard retourne les meilleurs scores
+func GetLeaderboard(store LeaderboardStore, limit int) (*LeaderboardPage, error) {
+	return QueryLeaderboard(store, LeaderboardQuery{Limit: limit})
+}
 
-	return filteredLeaderboard
+// GetLeaderboardByDifficulty retourne les meilleurs scores d'un niveau de difficulté
+func GetLeaderboardByDifficulty(store LeaderboardStore, difficulty string, limit int) (*LeaderboardPage, error) {
+	return QueryLeaderboard(store, LeaderboardQuery{Difficulty: difficulty, Limit: limit})
 }
@@ -0,0 +1,91 @@
+package game_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// addEntries enregistre des scores soumis à une seconde d'intervalle, dans l'ordre
+func addEntries(t *testing.T, store game.LeaderboardStore, at time.Time, entries ...game.LeaderboardEntry) {
+	t.Helper()
+	for i, entry := range entries {
+		entry.GameID = "G" + entry.ID
+		if entry.PlayerName == "" {
+			entry.PlayerName = entry.PlayerID
+		}
+		if entry.Difficulty == "" {
+			entry.Difficulty = "medium"
+		}
+		if entry.Language == "" {
+			entry.Language = "en"
+		}
+		if entry.SubmittedAt.IsZero() {
+			entry.SubmittedAt = at.Add(time.Duration(i) * time.Second)
+		}
+		if err := store.AddLeaderboardEntry(entry); err != nil {
+			t.Fatalf("AddLeaderboardEntry(%s): %v", entry.ID, err)
+		}
+	}
+}
+
+// pageRanks résume une page en couples ID/rang
+func pageRanks(page *game.LeaderboardPage) map[string]int {
+	ranks := make(map[string]int, len(page.Entries))
+	for _, entry := range page.Entries {
+		ranks[entry.ID] = entry.Rank
+	}
+	return ranks
+}
+
+// TestQueryLeaderboard vérifie la pagination du classement : les rangs suivent
+// la position dans le classement complet et les bornes invalides sont corrigées
+func TestQueryLeaderboard(t *testing.T) {
+	store := memory.New()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	addEntries(t, store, at,
+		game.LeaderboardEntry{ID: "E1", PlayerID: "alice", Score: 500},
+		game.LeaderboardEntry{ID: "E2", PlayerID: "bob", Score: 400, Difficulty: "hard"},
+		game.LeaderboardEntry{ID: "E3", PlayerID: "carol", Score: 300},
+		game.LeaderboardEntry{ID: "E4", PlayerID: "alice", Score: 200, Difficulty: "hard"},
+		game.LeaderboardEntry{ID: "E5", PlayerID: "dave", Score: 100},
+	)
+
+	tests := []struct {
+		name       string
+		query      game.LeaderboardQuery
+		want       map[string]int
+		wantOffset int
+		wantLimit  int
+	}{
+		{"second page", game.LeaderboardQuery{Offset: 2, Limit: 2}, map[string]int{"E3": 3, "E4": 4}, 2, 2},
+		{"past the end", game.LeaderboardQuery{Offset: 4, Limit: 2}, map[string]int{"E5": 5}, 4, 2},
+		{"negative offset", game.LeaderboardQuery{Offset: -3, Limit: 1}, map[string]int{"E1": 1}, 0, 1},
+		{"default limit", game.LeaderboardQuery{Offset: 3}, map[string]int{"E4": 4, "E5": 5}, 3, game.MaxLeaderboardLimit},
+		{"limit too large", game.LeaderboardQuery{Offset: 4, Limit: 1000}, map[string]int{"E5": 5}, 4, game.MaxLeaderboardLimit},
+		{"difficulty", game.LeaderboardQuery{Difficulty: "hard", Limit: 10}, map[string]int{"E2": 1, "E4": 2}, 0, 10},
+		{"best per player", game.LeaderboardQuery{BestPerPlayer: true, Offset: 1, Limit: 10}, map[string]int{"E2": 2, "E3": 3, "E5": 4}, 1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := game.QueryLeaderboard(store, tt.query)
+			if err != nil {
+				t.Fatalf("QueryLeaderboard: %v", err)
+			}
+			ranks := pageRanks(page)
+			if len(ranks) != len(tt.want) {
+				t.Fatalf("ranks = %v, want %v", ranks, tt.want)
+			}
+			for id, rank := range tt.want {
+				if ranks[id] != rank {
+					t.Fatalf("ranks = %v, want %v", ranks, tt.want)
+				}
+			}
+			if page.Offset != tt.wantOffset || page.Limit != tt.wantLimit {
+				t.Errorf("page offset %d, limit %d; want %d, %d", page.Offset, page.Limit, tt.wantOffset, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Erreurs liées aux saisons
+var (
+	ErrSeasonNotFound      = errors.New("season not found")
+	ErrSeasonNameTaken     = errors.New("season name already taken")
+	ErrSeasonArchived      = errors.New("season is already archived")
+	ErrInvalidSeason       = errors.New("season must have a name and end after it starts")
+	ErrInvalidSeasonLength = errors.New("season length must be daily, weekly or monthly")
+)
+
+// Statuts d'une saison, déduits de ses dates
+const (
+	SeasonScheduled = "scheduled"
+	SeasonActive    = "active"
+	SeasonEnded     = "ended" // terminée mais pas encore archivée
+	SeasonArchived  = "archived"
+)
+
+// Season est une compétition dont le classement regroupe les scores soumis
+// entre StartsAt (inclus) et EndsAt (exclu). À la fin de la saison, le
+// classement final est archivé et ne change plus.
+type Season struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	ArchivedAt time.Time `json:"archived_at,omitzero"`
+}
+
+// SeasonStore décrit le stockage des saisons et de leurs classements archivés
+type SeasonStore interface {
+	// CreateSeason enregistre une saison (utils.ErrDuplicateID si son ID est
+	// déjà utilisé, ErrSeasonNameTaken si son nom l'est)
+	CreateSeason(season *Season) error
+	// GetSeason récupère une saison par son ID (ErrSeasonNotFound si absente)
+	GetSeason(id string) (*Season, error)
+	// ListSeasons retourne toutes les saisons, les plus récentes en premier
+	ListSeasons() ([]Season, error)
+	// ArchiveSeason fige une copie des scores de la saison et la marque archivée
+	// (ErrSeasonNotFound si absente, ErrSeasonArchived si déjà archivée)
+	ArchiveSeason(id string, archivedAt time.Time) error
+	// ListSeasonEntries retourne une page du classement archivé d'une saison,
+	// comme LeaderboardStore.ListLeaderboardEntries (From, To et Period sont ignorés)
+	ListSeasonEntries(seasonID string, query LeaderboardQuery) ([]LeaderboardEntry, int, error)
+}
+
+// Status retourne le statut de la saison à l'instant now
+func (s *Season) Status(now time.Time) string {
+	switch {
+	case !s.ArchivedAt.IsZero():
+		return SeasonArchived
+	case now.Before(s.StartsAt):
+		return SeasonScheduled
+	case now.Before(s.EndsAt):
+		return SeasonActive
+	default:
+		return SeasonEnded
+	}
+}
+
+// Contains indique si t est compris dans la saison
+func (s *Season) Contains(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// CreateSeason crée une saison nommée entre deux dates
+func CreateSeason(store SeasonStore, name string, startsAt, endsAt time.Time) (*Season, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || !endsAt.After(startsAt) {
+		return nil, ErrInvalidSeason
+	}
+
+	season := &Season{Name: name, StartsAt: startsAt.UTC(), EndsAt: endsAt.UTC()}
+	err := utils.RetryOnDuplicateID(func() error {
+		season.ID = utils.NewID()
+		return store.CreateSeason(season)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return season, nil
+}
+
+// ListSeasons retourne toutes les saisons, les plus récentes en premier
+func ListSeasons(store SeasonStore) ([]Season, error) {
+	return store.ListSeasons()
+}
+
+// GetSeasonLeaderboard retourne une page du classement d'une saison. Une saison
+// archivée renvoie son classement final ; sinon les scores sont lus en direct
+// sur la période de la saison. Le filtre de difficulté reclasse les entrées
+// comme GetLeaderboardByDifficulty.
+func GetSeasonLeaderboard(lb LeaderboardStore, seasons SeasonStore, seasonID string, query LeaderboardQuery) (*Season, *LeaderboardPage, error) {
+	season, err := seasons.GetSeason(seasonID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query.Period = ""
+	query.From, query.To = season.StartsAt, season.EndsAt
+	if season.ArchivedAt.IsZero() {
+		page, err := QueryLeaderboard(lb, query)
+		return season, page, err
+	}
+
+	page, err := queryLeaderboard(query, func(q LeaderboardQuery) ([]LeaderboardEntry, int, error) {
+		return seasons.ListSeasonEntries(season.ID, q)
+	})
+	return season, page, err
+}
+
+// RolloverSeasons archive les saisons terminées puis, si length vaut "daily",
+// "weekly" ou "monthly", crée la saison de la période courante quand aucune
+// saison n'est en cours. Plusieurs serveurs peuvent l'appeler en même temps.
+func RolloverSeasons(store SeasonStore, now time.Time, length string) error {
+	seasons, err := store.ListSeasons()
+	if err != nil {
+		return err
+	}
+
+	current := false
+	for _, season := range seasons {
+		if season.Status(now) == SeasonEnded {
+			err := store.ArchiveSeason(season.ID, now)
+			if err != nil && !errors.Is(err, ErrSeasonArchived) {
+				return fmt.Errorf("archiving season %q: %w", season.Name, err)
+			}
+			if err == nil {
+				log.Printf("archived season %q", season.Name)
+			}
+			continue
+		}
+		if season.Contains(now) {
+			current = true
+		}
+	}
+
+	if length == "" || current {
+		return nil
+	}
+
+	if err := ValidateSeasonLength(length); err != nil {
+		return err
+	}
+
+	from, to, err := PeriodRange(length, now)
+	if err != nil {
+		return err
+	}
+
+	season, err := CreateSeason(store, seasonName(length, from), from, to)
+	if errors.Is(err, ErrSeasonNameTaken) {
+		// Un autre serveur l'a créée entre-temps
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("started season %q", season.Name)
+	return nil
+}
+
+// ValidateSeasonLength vérifie une durée de saison automatique ("" = désactivée)
+func ValidateSeasonLength(length string) error {
+	switch length {
+	case "", PeriodDaily, PeriodWeekly, PeriodMonthly:
+		return nil
+	}
+	return ErrInvalidSeasonLength
+}
+
+// StartSeasonRollover appelle périodiquement RolloverSeasons, une première fois
+// immédiatement. La fonction retournée arrête la rotation.
+func StartSeasonRollover(store SeasonStore, interval time.Duration, length string) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	rollover := func() {
+		if err := RolloverSeasons(store, time.Now(), length); err != nil {
+			log.Printf("rolling over seasons: %v", err)
+		}
+	}
+
+	go func() {
+		rollover()
+		for {
+			select {
+			case <-ticker.C:
+				rollover()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// seasonName nomme une saison créée automatiquement d'après sa période :
+// "2026-10-18" (jour), "2026-W42" (semaine ISO) ou "2026-10" (mois)
+func seasonName(length string, from time.Time) string {
+	from = from.In(LeaderboardLocation)
+	switch length {
+	case PeriodDaily:
+		return from.Format("2006-01-02")
+	case PeriodWeekly:
+		year, week := from.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return from.Format("2006-01")
+	}
+}
@@ -0,0 +1,87 @@
+package game_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// TestRolloverSeasons vérifie la rotation hebdomadaire : la saison de la
+// semaine est créée une seule fois, puis archivée la semaine suivante avec son
+// classement final pendant que la nouvelle saison lit les scores en direct
+func TestRolloverSeasons(t *testing.T) {
+	store := memory.New()
+	// Mercredi de la semaine ISO 42
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	for range 2 {
+		if err := game.RolloverSeasons(store, now, game.PeriodWeekly); err != nil {
+			t.Fatalf("RolloverSeasons: %v", err)
+		}
+	}
+	seasons, err := game.ListSeasons(store)
+	if err != nil {
+		t.Fatalf("ListSeasons: %v", err)
+	}
+	if len(seasons) != 1 || seasons[0].Name != "2026-W42" || seasons[0].Status(now) != game.SeasonActive {
+		t.Fatalf("seasons after two rollovers = %+v, want the active season 2026-W42", seasons)
+	}
+	first := seasons[0]
+	wantStart := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	if !first.StartsAt.Equal(wantStart) || !first.EndsAt.Equal(wantStart.AddDate(0, 0, 7)) {
+		t.Errorf("season 2026-W42 runs [%v, %v), want the week starting %v", first.StartsAt, first.EndsAt, wantStart)
+	}
+
+	addEntries(t, store, now,
+		game.LeaderboardEntry{ID: "E1", PlayerID: "alice", Score: 200},
+		game.LeaderboardEntry{ID: "E2", PlayerID: "bob", Score: 300},
+	)
+
+	// La semaine suivante, la saison terminée est archivée et remplacée
+	next := now.AddDate(0, 0, 7)
+	if err := game.RolloverSeasons(store, next, game.PeriodWeekly); err != nil {
+		t.Fatalf("RolloverSeasons(next week): %v", err)
+	}
+	seasons, err = game.ListSeasons(store)
+	if err != nil {
+		t.Fatalf("ListSeasons: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, season := range seasons {
+		statuses[season.Name] = season.Status(next)
+	}
+	if len(seasons) != 2 || statuses["2026-W42"] != game.SeasonArchived || statuses["2026-W43"] != game.SeasonActive {
+		t.Fatalf("seasons after a week = %v, want 2026-W42 archived and 2026-W43 active", statuses)
+	}
+
+	addEntries(t, store, next,
+		game.LeaderboardEntry{ID: "E3", PlayerID: "carol", Score: 100},
+		// Soumis tardivement mais daté de la semaine précédente
+		game.LeaderboardEntry{ID: "E4", PlayerID: "dave", Score: 900, SubmittedAt: now.Add(time.Hour)},
+	)
+
+	checkSeason := func(id string, want map[string]int) {
+		t.Helper()
+		_, page, err := game.GetSeasonLeaderboard(store, store, id, game.LeaderboardQuery{})
+		if err != nil {
+			t.Fatalf("GetSeasonLeaderboard: %v", err)
+		}
+		ranks := pageRanks(page)
+		if len(ranks) != len(want) {
+			t.Fatalf("season leaderboard = %v, want %v", ranks, want)
+		}
+		for entryID, rank := range want {
+			if ranks[entryID] != rank {
+				t.Fatalf("season leaderboard = %v, want %v", ranks, want)
+			}
+		}
+	}
+	checkSeason(first.ID, map[string]int{"E2": 1, "E1": 2})
+	for _, season := range seasons {
+		if season.Name == "2026-W43" {
+			checkSeason(season.ID, map[string]int{"E3": 1})
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"math"
+	"time"
+)
+
+// MaxStandingNeighbors borne le nombre d'entrées renvoyées autour d'un joueur
+const MaxStandingNeighbors = 10
+
+// PlayerStanding décrit la position d'un joueur dans un classement
+type PlayerStanding struct {
+	PlayerID   string        `json:"player_id"`
+	Best       RankedEntry   `json:"best"`       // meilleure entrée du joueur et son rang
+	Total      int           `json:"total"`      // nombre d'entrées du classement
+	Percentile float64       `json:"percentile"` // part du classement au rang du joueur ou en dessous
+	Above      []RankedEntry `json:"above"`      // entrées classées juste avant, de la plus proche à la plus lointaine
+	Below      []RankedEntry `json:"below"`      // entrées classées juste après, de la plus proche à la plus lointaine
+}
+
+// GetPlayerStanding retourne la meilleure entrée d'un joueur, son rang, son
+// percentile et jusqu'à neighbors entrées de part et d'autre. Offset et Limit
+// de la requête sont ignorés (ErrPlayerNotRanked si le joueur n'a aucun score).
+func GetPlayerStanding(store LeaderboardStore, playerID string, query LeaderboardQuery, neighbors int) (*PlayerStanding, error) {
+	if query.Period != "" {
+		from, to, err := PeriodRange(query.Period, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		query.From, query.To = from, to
+	}
+	neighbors = max(0, min(neighbors, MaxStandingNeighbors))
+
+	best, rank, err := store.PlayerStanding(playerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lire d'un coup les voisins du dessus, le joueur et ceux du dessous
+	query.Offset = max(0, rank-1-neighbors)
+	query.Limit = rank + neighbors - query.Offset
+	entries, total, err := store.ListLeaderboardEntries(query)
+	if err != nil {
+		return nil, err
+	}
+
+	standing := &PlayerStanding{
+		PlayerID:   playerID,
+		Best:       RankedEntry{Rank: rank, LeaderboardEntry: *best},
+		Total:      total,
+		Percentile: percentile(rank, total),
+		Above:      []RankedEntry{},
+		Below:      []RankedEntry{},
+	}
+	for i, entry := range entries {
+		entryRank := query.Offset + i + 1
+		switch {
+		case entryRank < rank:
+			standing.Above = append([]RankedEntry{{Rank: entryRank, LeaderboardEntry: entry}}, standing.Above...)
+		case entryRank > rank:
+			standing.Below = append(standing.Below, RankedEntry{Rank: entryRank, LeaderboardEntry: entry})
+		}
+	}
+
+	return standing, nil
+}
+
+// percentile retourne la part du classement (en %, arrondie au dixième) classée
+// au rang donné ou en dessous : 100 pour le premier, 100/total pour le dernier
+func percentile(rank, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	p := float64(total-rank+1) / float64(total) * 100
+	return math.Round(p*10) / 10
+}
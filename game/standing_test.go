@@ -0,0 +1,82 @@
+package game_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// entryRanks résume des entrées classées en rangs, dans leur ordre
+func entryRanks(entries []game.RankedEntry) []int {
+	ranks := []int{}
+	for _, entry := range entries {
+		ranks = append(ranks, entry.Rank)
+	}
+	return ranks
+}
+
+// TestPlayerStanding vérifie le rang, le percentile et les voisins d'un joueur,
+// du plus proche au plus lointain, tronqués aux bords du classement
+func TestPlayerStanding(t *testing.T) {
+	store := memory.New()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	// P1 à P15 classés dans l'ordre, P5 ayant aussi un score moins bon
+	var entries []game.LeaderboardEntry
+	for i := 1; i <= 15; i++ {
+		entries = append(entries, game.LeaderboardEntry{ID: fmt.Sprintf("E%02d", i), PlayerID: fmt.Sprintf("P%d", i), Score: 1000 - i*10})
+	}
+	entries = append(entries, game.LeaderboardEntry{ID: "E99", PlayerID: "P5", Score: 1})
+	addEntries(t, store, at, entries...)
+
+	tests := []struct {
+		name           string
+		playerID       string
+		neighbors      int
+		wantRank       int
+		wantPercentile float64
+		wantAbove      []int
+		wantBelow      []int
+	}{
+		{"middle", "P5", 2, 5, 75, []int{4, 3}, []int{6, 7}},
+		{"first", "P1", 3, 1, 100, []int{}, []int{2, 3, 4}},
+		{"near the top", "P2", 3, 2, 93.8, []int{1}, []int{3, 4, 5}},
+		{"last", "P15", 2, 15, 12.5, []int{14, 13}, []int{16}},
+		{"no neighbors", "P8", 0, 8, 56.3, []int{}, []int{}},
+		{"negative neighbors", "P8", -4, 8, 56.3, []int{}, []int{}},
+		{"too many neighbors", "P1", 50, 1, 100, []int{}, []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standing, err := game.GetPlayerStanding(store, tt.playerID, game.LeaderboardQuery{}, tt.neighbors)
+			if err != nil {
+				t.Fatalf("GetPlayerStanding: %v", err)
+			}
+			if standing.Best.Rank != tt.wantRank || standing.Total != 16 || standing.Percentile != tt.wantPercentile {
+				t.Errorf("rank %d of %d, percentile %v; want %d of 16, %v",
+					standing.Best.Rank, standing.Total, standing.Percentile, tt.wantRank, tt.wantPercentile)
+			}
+			above, below := entryRanks(standing.Above), entryRanks(standing.Below)
+			if fmt.Sprint(above) != fmt.Sprint(tt.wantAbove) || fmt.Sprint(below) != fmt.Sprint(tt.wantBelow) {
+				t.Errorf("neighbors above %v, below %v; want %v, %v", above, below, tt.wantAbove, tt.wantBelow)
+			}
+		})
+	}
+
+	// Un meilleur score par joueur : P5 ne compte qu'une fois
+	standing, err := game.GetPlayerStanding(store, "P15", game.LeaderboardQuery{BestPerPlayer: true}, 1)
+	if err != nil {
+		t.Fatalf("GetPlayerStanding(best per player): %v", err)
+	}
+	if standing.Best.Rank != 15 || standing.Total != 15 || standing.Percentile != 6.7 || len(standing.Below) != 0 {
+		t.Errorf("best per player: rank %d of %d, percentile %v, %d below; want 15 of 15, 6.7, 0 below",
+			standing.Best.Rank, standing.Total, standing.Percentile, len(standing.Below))
+	}
+
+	if _, err := game.GetPlayerStanding(store, "nobody", game.LeaderboardQuery{}, 2); !errors.Is(err, game.ErrPlayerNotRanked) {
+		t.Errorf("GetPlayerStanding(unranked player) = %v, want %v", err, game.ErrPlayerNotRanked)
+	}
+}
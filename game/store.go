@@ -0,0 +1,52 @@
+package game
+
+import "errors"
+
+// Erreurs renvoyées par les implémentations de stockage et les opérations de jeu
+var (
+	ErrGameNotFound          = errors.New("game not found")
+	ErrGameOver              = errors.New("game is already completed")
+	ErrInvalidLetter         = errors.New("guess must be a single letter")
+	ErrGameConflict          = errors.New("game was modified concurrently")
+	ErrGameInProgress        = errors.New("game is not finished")
+	ErrNotGamePlayer         = errors.New("game belongs to another player")
+	ErrScoreAlreadySubmitted = errors.New("score already submitted for this game")
+	ErrPlayerNotRanked       = errors.New("player has no score on this leaderboard")
+)
+
+// GameStore décrit le stockage des parties
+type GameStore interface {
+	// CreateGame enregistre une nouvelle partie (utils.ErrDuplicateID si son ID
+	// ou son code de partage est déjà utilisé)
+	CreateGame(g *Game) error
+	// SaveGame met à jour une partie existante si sa version n'a pas changé depuis
+	// sa lecture, puis incrémente g.Version (ErrGameNotFound si absente,
+	// ErrGameConflict si elle a été enregistrée entre-temps)
+	SaveGame(g *Game) error
+	// GetGame récupère une partie par son ID (ErrGameNotFound si absente)
+	GetGame(id string) (*Game, error)
+	// GetGameByShareCode récupère une partie par son code de partage (ErrGameNotFound si absente)
+	GetGameByShareCode(code string) (*Game, error)
+	// DeleteGame supprime une partie (ErrGameNotFound si absente)
+	DeleteGame(id string) error
+	// WordStats retourne, pour chaque mot et langue joués, le nombre de parties
+	// terminées (gagnées ou perdues) et le nombre de parties gagnées
+	WordStats() ([]WordStats, error)
+}
+
+// LeaderboardStore décrit le stockage des scores du classement
+type LeaderboardStore interface {
+	// AddLeaderboardEntry ajoute un score au classement (utils.ErrDuplicateID si
+	// son ID est déjà utilisé, ErrScoreAlreadySubmitted si sa partie a déjà un score)
+	AddLeaderboardEntry(entry LeaderboardEntry) error
+	// SubmitLeaderboardEntry ajoute un score comme AddLeaderboardEntry et, dans la
+	// même transaction, le compte dans les statistiques de son joueur avec
+	// models.User.RecordGame (models.ErrUserNotFound si le joueur n'existe pas)
+	SubmitLeaderboardEntry(entry LeaderboardEntry, won bool) error
+	// ListLeaderboardEntries retourne une page des scores correspondant à la requête,
+	// dans l'ordre de LeaderboardEntry.RanksBefore, ainsi que leur nombre total
+	ListLeaderboardEntries(query LeaderboardQuery) ([]LeaderboardEntry, int, error)
+	// PlayerStanding retourne la meilleure entrée d'un joueur parmi celles
+	// correspondant à la requête et son rang (ErrPlayerNotRanked si aucune)
+	PlayerStanding(playerID string, query LeaderboardQuery) (*LeaderboardEntry, int, error)
+}
@@ -0,0 +1,420 @@
+package game
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Difficulties liste les niveaux de difficulté que doit couvrir chaque langue
+var Difficulties = []string{"easy", "medium", "hard"}
+
+// ErrInvalidWordFile regroupe les erreurs de validation des fichiers de mots
+var ErrInvalidWordFile = errors.New("invalid word file")
+
+// WordRecord est une ligne d'un fichier de mots (JSON, YAML ou CSV)
+type WordRecord struct {
+	Word       string   `json:"word" yaml:"word"`
+	Hint       string   `json:"hint" yaml:"hint"`
+	Difficulty string   `json:"difficulty" yaml:"difficulty"` // classée par ClassifyWord si vide ou "auto"
+	Category   string   `json:"category,omitempty" yaml:"category,omitempty"`
+	Tags       []string `json:"tags" yaml:"tags"`
+	Language   string   `json:"language" yaml:"language"`                   // DefaultLanguage si vide
+	Enabled    *bool    `json:"enabled,omitempty" yaml:"enabled,omitempty"` // true si absent
+}
+
+// Formats des fichiers de mots
+const (
+	WordFormatJSON = "json"
+	WordFormatYAML = "yaml"
+	WordFormatCSV  = "csv"
+)
+
+// ErrUnknownWordFormat est renvoyée pour un format de fichier de mots inconnu
+var ErrUnknownWordFormat = errors.New("word format must be json, yaml or csv")
+
+// wordFileExtensions associe les extensions reconnues à leur format
+var wordFileExtensions = map[string]string{
+	".json": WordFormatJSON,
+	".yaml": WordFormatYAML,
+	".yml":  WordFormatYAML,
+	".csv":  WordFormatCSV,
+}
+
+// ReadWordRecords décode des mots au format donné, sans les valider
+func ReadWordRecords(r io.Reader, format string) ([]WordRecord, error) {
+	switch format {
+	case WordFormatJSON:
+		return readJSONWords(r)
+	case WordFormatYAML:
+		return readYAMLWords(r)
+	case WordFormatCSV:
+		return readCSVWords(r)
+	}
+	return nil, ErrUnknownWordFormat
+}
+
+// WriteWordRecords encode des mots au format donné, relisible par ReadWordRecords
+func WriteWordRecords(w io.Writer, format string, records []WordRecord) error {
+	switch format {
+	case WordFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case WordFormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case WordFormatCSV:
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"word", "hint", "difficulty", "category", "tags", "language", "enabled"})
+		for _, record := range records {
+			enabled := record.Enabled == nil || *record.Enabled
+			writer.Write([]string{record.Word, record.Hint, record.Difficulty, record.Category,
+				strings.Join(record.Tags, ";"), record.Language, strconv.FormatBool(enabled)})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return ErrUnknownWordFormat
+}
+
+// readWordRecordsDir décode les fichiers de mots d'un répertoire. sources[i]
+// situe records[i] (fichier et rang) pour les messages d'erreur.
+func readWordRecordsDir(dir string) (records []WordRecord, sources []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range entries {
+		format, ok := wordFileExtensions[strings.ToLower(filepath.Ext(entry.Name()))]
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		fileRecords, err := readWordFile(path, format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidWordFile, path, err)
+		}
+		for i := range fileRecords {
+			records = append(records, fileRecords[i])
+			sources = append(sources, fmt.Sprintf("%s: entry %d", entry.Name(), i+1))
+		}
+	}
+
+	return records, sources, nil
+}
+
+// readWordFile ouvre un fichier de mots et le décode selon son format
+func readWordFile(path string, format string) ([]WordRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadWordRecords(f, format)
+}
+
+// buildCatalog valide les mots lus et range ceux qui sont activés par langue et
+// difficulté. sources[i] situe records[i] dans les messages d'erreur.
+func buildCatalog(records []WordRecord, sources []string) (wordCatalog, int, error) {
+	normalized, errs := normalizeRecords(records, sources)
+
+	catalog := wordCatalog{}
+	for _, record := range normalized {
+		if record.Enabled != nil && !*record.Enabled {
+			continue
+		}
+		if catalog[record.Language] == nil {
+			catalog[record.Language] = map[string][]WordWithHint{}
+		}
+		catalog[record.Language][record.Difficulty] = append(catalog[record.Language][record.Difficulty],
+			WordWithHint{Word: record.Word, Hint: record.Hint, Category: record.Category, Tags: record.Tags})
+	}
+
+	if len(records) == 0 {
+		errs = append(errs, errors.New("no words found"))
+	}
+	if _, exists := catalog[DefaultLanguage]; !exists && len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("no words for the default language %q", DefaultLanguage))
+	}
+	for language, words := range catalog {
+		for _, difficulty := range Difficulties {
+			if len(words[difficulty]) == 0 {
+				errs = append(errs, fmt.Errorf("language %q has no %s words", language, difficulty))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, 0, fmt.Errorf("%w:\n%w", ErrInvalidWordFile, errors.Join(errs...))
+	}
+	return catalog, len(records), nil
+}
+
+// normalizeRecords normalise des mots et retourne ceux qui sont valides, ainsi
+// qu'une erreur par mot invalide ou en double (même langue, accents et casse ignorés)
+func normalizeRecords(records []WordRecord, sources []string) ([]WordRecord, []error) {
+	normalized := make([]WordRecord, 0, len(records))
+	seen := map[string]string{} // langue + mot -> source de sa première occurrence
+	var errs []error
+
+	for i, record := range records {
+		word, err := normalizeRecord(&record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sources[i], err))
+			continue
+		}
+
+		key := record.Language + "/" + WordKey(word)
+		if first, exists := seen[key]; exists {
+			errs = append(errs, fmt.Errorf("%s: duplicate word %q (first defined in %s)", sources[i], word, first))
+			continue
+		}
+		seen[key] = sources[i]
+
+		normalized = append(normalized, record)
+	}
+
+	return normalized, errs
+}
+
+// WordKey retourne la forme sous laquelle deux mots d'une même langue sont
+// considérés identiques (majuscules, sans accents)
+func WordKey(word string) string {
+	return foldWord(sanitizeWord(word), MatchFold)
+}
+
+// normalizeRecord complète et vérifie une ligne de fichier de mots, dont le mot
+// est normalisé (majuscules, espaces uniques) et retourné
+func normalizeRecord(record *WordRecord) (string, error) {
+	word := sanitizeWord(record.Word)
+	if word == "" {
+		return "", errors.New("empty word")
+	}
+	letters := 0
+	for _, r := range word {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case r != ' ' && r != '-' && r != '\'' && r != '’':
+			return "", fmt.Errorf("word %q contains invalid character %q", word, r)
+		}
+	}
+	if letters == 0 {
+		return "", fmt.Errorf("word %q contains no letter", word)
+	}
+
+	record.Hint = strings.TrimSpace(record.Hint)
+	if record.Hint == "" {
+		return "", fmt.Errorf("word %q has an empty hint", word)
+	}
+
+	// Sans difficulté, le mot est classé par ClassifyWord
+	record.Difficulty = strings.ToLower(strings.TrimSpace(record.Difficulty))
+	if record.Difficulty == "" || record.Difficulty == DifficultyAuto {
+		record.Difficulty = ClassifyWord(word, WordStats{}).Difficulty
+	}
+	if !utils.Contains(Difficulties, record.Difficulty) {
+		return "", fmt.Errorf("word %q has invalid difficulty %q", word, record.Difficulty)
+	}
+
+	record.Category = strings.ToLower(strings.TrimSpace(record.Category))
+	if !validCategory(record.Category) {
+		return "", fmt.Errorf("word %q has invalid category %q", word, record.Category)
+	}
+
+	record.Language = strings.ToLower(strings.TrimSpace(record.Language))
+	if record.Language == "" {
+		record.Language = DefaultLanguage
+	}
+	if !validLanguageCode(record.Language) {
+		return "", fmt.Errorf("word %q has invalid language %q", word, record.Language)
+	}
+
+	tags := make([]string, 0, len(record.Tags))
+	for _, tag := range record.Tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	record.Tags = tags
+	record.Word = word
+
+	return word, nil
+}
+
+// validLanguageCode vérifie qu'une langue est un code ISO 639-1 ("en", "fr")
+func validLanguageCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// readJSONWords lit un tableau JSON de mots
+func readJSONWords(r io.Reader) ([]WordRecord, error) {
+	var records []WordRecord
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// readYAMLWords lit une liste YAML de mots
+func readYAMLWords(r io.Reader) ([]WordRecord, error) {
+	var records []WordRecord
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&records); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return records, nil
+}
+
+// readCSVWords lit un fichier CSV dont la première ligne nomme les colonnes, dans
+// un ordre quelconque : word (obligatoire), hint, difficulty, category, tags,
+// language et enabled. Les étiquettes sont séparées par ";".
+func readCSVWords(r io.Reader) ([]WordRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "word", "hint", "difficulty", "category", "tags", "language", "enabled":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	if _, ok := columns["word"]; !ok {
+		return nil, errors.New("missing word column")
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return row[i]
+		}
+		return ""
+	}
+
+	var records []WordRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := WordRecord{
+			Word:       field(row, "word"),
+			Hint:       field(row, "hint"),
+			Difficulty: field(row, "difficulty"),
+			Category:   field(row, "category"),
+			Language:   field(row, "language"),
+		}
+		if tags := field(row, "tags"); tags != "" {
+			record.Tags = strings.Split(tags, ";")
+		}
+		if enabled := strings.TrimSpace(field(row, "enabled")); enabled != "" {
+			value, err := strconv.ParseBool(enabled)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid enabled value %q", len(records)+2, enabled)
+			}
+			record.Enabled = &value
+		}
+		records = append(records, record)
+	}
+}
+
+// wordReloadDelay regroupe les événements rapprochés (un éditeur écrit souvent
+// un fichier en plusieurs fois) en un seul rechargement
+const wordReloadDelay = 250 * time.Millisecond
+
+// WatchWordDir appelle load (ImportWordDir par exemple) à chaque modification
+// du répertoire. Un rechargement invalide est journalisé et les mots
+// précédents conservés. La fonction retournée arrête la surveillance.
+func WatchWordDir(dir string, load func(dir string) (int, error)) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if _, known := wordFileExtensions[strings.ToLower(filepath.Ext(event.Name))]; known {
+					reload = time.After(wordReloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("watching word files: %v", err)
+			case <-reload:
+				reload = nil
+				count, err := load(dir)
+				if err != nil {
+					log.Printf("reloading word files, keeping previous word lists: %v", err)
+					continue
+				}
+				log.Printf("reloaded word files from %s: %d words added, changed or disabled", dir, count)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		watcher.Close()
+	}, nil
+}
@@ -1,8 +1,7 @@
 package game
 
 import (
-	"math/rand"
-	"time"
+	"sync/atomic"
 )
 
 // Liste des mots disponibles pour le jeu
@@ -17,86 +16,160 @@ var wordList = []string{
 
 // Structure pour stocker un mot et son indice
 type WordWithHint struct {
-	Word string
-	Hint string
+	Word     string
+	Hint     string
+	Category string   // thème du mot ("consoles", "characters"...), vide si aucun
+	Tags     []string // étiquettes libres venant des fichiers de mots
 }
 
-// Catégories de mots pour des niveaux de difficulté avec indices
-var wordCategories = map[string][]WordWithHint{
+// wordCatalog regroupe les mots par langue puis par niveau de difficulté, les
+// indices étant rédigés dans la langue du catalogue
+type wordCatalog map[string]map[string][]WordWithHint
+
+// builtinCatalog est le catalogue utilisé tant qu'aucun fichier de mots n'est chargé
+var builtinCatalog = wordCatalog{
+	"en": englishWords,
+	"fr": frenchWords,
+}
+
+// wordCatalogs est le catalogue courant. Il n'est jamais modifié en place : un
+// rechargement le remplace d'un bloc (voir RefreshWordCatalog).
+var wordCatalogs atomic.Pointer[wordCatalog]
+
+// Mots anglais
+var englishWords = map[string][]WordWithHint{
+	"easy": {
+		{Word: "MARIO", Hint: "Famous Italian plumber", Category: "characters"},
+		{Word: "SONIC", Hint: "Fast blue hedgehog", Category: "characters"},
+		{Word: "LINK", Hint: "Hero of the Triforce", Category: "characters"},
+		{Word: "TETRIS", Hint: "Falling blocks game", Category: "games"},
+		{Word: "PACMAN", Hint: "Yellow character eating dots", Category: "games"},
+		{Word: "PIXEL", Hint: "Smallest unit of a digital image", Category: "technology"},
+		{Word: "ARCADE", Hint: "Video game venue", Category: "culture"},
+		{Word: "RETRO", Hint: "Old-school nostalgic style", Category: "culture"},
+		{Word: "KIRBY", Hint: "Pink ball that inhales enemies", Category: "characters"},
+		{Word: "PONG", Hint: "One of the first video games (table tennis)", Category: "games"},
+	},
+	"medium": {
+		{Word: "NINTENDO", Hint: "Japanese video game company", Category: "companies"},
+		{Word: "GAMEBOY", Hint: "Monochrome handheld console", Category: "consoles"},
+		{Word: "POKEMON", Hint: "Creatures to catch and train", Category: "games"},
+		{Word: "CONSOLE", Hint: "Device dedicated to gaming", Category: "technology"},
+		{Word: "CONTROLLER", Hint: "Gaming input device", Category: "technology"},
+		{Word: "JOYSTICK", Hint: "Directional control lever", Category: "technology"},
+		{Word: "PIKACHU", Hint: "Electric mouse", Category: "characters"},
+		{Word: "DONKEY", Hint: "Famous gorilla in video games", Category: "characters"},
+		{Word: "SPRITE", Hint: "2D image integrated in a scene", Category: "technology"},
+		{Word: "MEGAMAN", Hint: "Blue robot fighting other robots", Category: "characters"},
+		{Word: "ATARI", Hint: "Pioneer of gaming consoles", Category: "companies"},
+	},
+	"hard": {
+		{Word: "PLAYSTATION", Hint: "Sony's gaming console", Category: "consoles"},
+		{Word: "CASTLEVANIA", Hint: "Vampire hunting game", Category: "games"},
+		{Word: "MEGADRIVE", Hint: "Sega's 16-bit console", Category: "consoles"},
+		{Word: "METROID", Hint: "Space adventure with Samus Aran", Category: "games"},
+		{Word: "BOMBERMAN", Hint: "Game about placing bombs in a maze", Category: "games"},
+		{Word: "FINALFANTASY", Hint: "Legendary Japanese RPG series", Category: "games"},
+		{Word: "STREETSOFRAGE", Hint: "Sega's beat'em up game", Category: "games"},
+		{Word: "MORTALKOMBAT", Hint: "Fighting game with fatalities", Category: "games"},
+		{Word: "RESIDENTEVIL", Hint: "Horror game with zombies", Category: "games"},
+		{Word: "METALSLUG", Hint: "Run and gun with vehicles", Category: "games"},
+	},
+}
+
+// Mots français
+var frenchWords = map[string][]WordWithHint{
 	"easy": {
-		{Word: "MARIO", Hint: "Famous Italian plumber"},
-		{Word: "SONIC", Hint: "Fast blue hedgehog"},
-		{Word: "LINK", Hint: "Hero of the Triforce"},
-		{Word: "TETRIS", Hint: "Falling blocks game"},
-		{Word: "PACMAN", Hint: "Yellow character eating dots"},
-		{Word: "PIXEL", Hint: "Smallest unit of a digital image"},
-		{Word: "ARCADE", Hint: "Video game venue"},
-		{Word: "RETRO", Hint: "Old-school nostalgic style"},
-		{Word: "KIRBY", Hint: "Pink ball that inhales enemies"},
-		{Word: "PONG", Hint: "One of the first video games (table tennis)"},
+		{Word: "MARIO", Hint: "Célèbre plombier italien", Category: "characters"},
+		{Word: "SONIC", Hint: "Hérisson bleu très rapide", Category: "characters"},
+		{Word: "TETRIS", Hint: "Jeu de blocs qui tombent", Category: "games"},
+		{Word: "PIXEL", Hint: "Plus petit point d'une image numérique", Category: "technology"},
+		{Word: "MANETTE", Hint: "Se tient à deux mains pour jouer", Category: "technology"},
+		{Word: "ÉCRAN", Hint: "Là où s'affiche le jeu", Category: "technology"},
+		{Word: "RÉTRO", Hint: "Style à l'ancienne et nostalgique", Category: "culture"},
+		{Word: "NIVEAU", Hint: "Étape d'un jeu à terminer", Category: "culture"},
+		{Word: "KIRBY", Hint: "Boule rose qui avale ses ennemis", Category: "characters"},
+		{Word: "BORNE", Hint: "Meuble de jeu des salles d'arcade", Category: "culture"},
 	},
 	"medium": {
-		{Word: "NINTENDO", Hint: "Japanese video game company"},
-		{Word: "GAMEBOY", Hint: "Monochrome handheld console"},
-		{Word: "POKEMON", Hint: "Creatures to catch and train"},
-		{Word: "CONSOLE", Hint: "Device dedicated to gaming"},
-		{Word: "CONTROLLER", Hint: "Gaming input device"},
-		{Word: "JOYSTICK", Hint: "Directional control lever"},
-		{Word: "PIKACHU", Hint: "Electric mouse"},
-		{Word: "DONKEY", Hint: "Famous gorilla in video games"},
-		{Word: "SPRITE", Hint: "2D image integrated in a scene"},
-		{Word: "MEGAMAN", Hint: "Blue robot fighting other robots"},
-		{Word: "ATARI", Hint: "Pioneer of gaming consoles"},
+		{Word: "CONSOLE", Hint: "Appareil dédié au jeu vidéo", Category: "technology"},
+		{Word: "CARTOUCHE", Hint: "Support de jeu à insérer dans la console", Category: "technology"},
+		{Word: "POKÉMON", Hint: "Créatures à capturer et entraîner", Category: "games"},
+		{Word: "SAUVEGARDE", Hint: "Permet de reprendre sa partie plus tard", Category: "technology"},
+		{Word: "MANCHE À BALAI", Hint: "Levier de contrôle directionnel", Category: "technology"},
+		{Word: "PIKACHU", Hint: "Souris électrique", Category: "characters"},
+		{Word: "DÉFI", Hint: "Épreuve lancée au joueur", Category: "culture"},
+		{Word: "MEILLEUR SCORE", Hint: "Record affiché en haut de l'écran", Category: "culture"},
+		{Word: "SPRITE", Hint: "Image 2D intégrée à une scène", Category: "technology"},
+		{Word: "ATARI", Hint: "Pionnier des consoles de jeu", Category: "companies"},
 	},
 	"hard": {
-		{Word: "PLAYSTATION", Hint: "Sony's gaming console"},
-		{Word: "CASTLEVANIA", Hint: "Vampire hunting game"},
-		{Word: "MEGADRIVE", Hint: "Sega's 16-bit console"},
-		{Word: "METROID", Hint: "Space adventure with Samus Aran"},
-		{Word: "BOMBERMAN", Hint: "Game about placing bombs in a maze"},
-		{Word: "FINALFANTASY", Hint: "Legendary Japanese RPG series"},
-		{Word: "STREETSOFRAGE", Hint: "Sega's beat'em up game"},
-		{Word: "MORTALKOMBAT", Hint: "Fighting game with fatalities"},
-		{Word: "RESIDENTEVIL", Hint: "Horror game with zombies"},
-		{Word: "METALSLUG", Hint: "Run and gun with vehicles"},
+		{Word: "CASSE-BRIQUES", Hint: "Une balle, une raquette et un mur à démolir", Category: "genres"},
+		{Word: "JEU DE PLATEFORMES", Hint: "Genre où l'on saute d'une corniche à l'autre", Category: "genres"},
+		{Word: "CASTLEVANIA", Hint: "Chasse aux vampires dans un château", Category: "games"},
+		{Word: "MÉGADRIVE", Hint: "Console 16 bits de Sega", Category: "consoles"},
+		{Word: "ÉCRAN TITRE", Hint: "Premier écran affiché au lancement du jeu", Category: "culture"},
+		{Word: "L'ÂGE D'OR", Hint: "Époque bénie des salles d'arcade", Category: "culture"},
+		{Word: "TÉLÉVISEUR CATHODIQUE", Hint: "Gros écran bombé des années 80", Category: "technology"},
+		{Word: "BOMBERMAN", Hint: "Poseur de bombes dans un labyrinthe", Category: "games"},
+		{Word: "SALLE D'ARCADE", Hint: "Lieu rempli de bornes et de jetons", Category: "culture"},
 	},
 }
 
-// Initialiser le générateur de nombres aléatoires
+// Initialiser le catalogue intégré
 func init() {
-	rand.Seed(time.Now().UnixNano())
+	wordCatalogs.Store(&builtinCatalog)
 }
 
 // GetRandomWord retourne un mot aléatoire de la liste
 func GetRandomWord() string {
-	return wordList[rand.Intn(len(wordList))]
+	return wordList[randomSource.Intn(len(wordList))]
 }
 
 // WordSelection contient un mot et son indice
 type WordSelection struct {
-	Word string `json:"word"`
-	Hint string `json:"hint"`
+	Word     string `json:"word"`
+	Hint     string `json:"hint"`
+	Language string `json:"language,omitempty"` // langue du catalogue d'où vient le mot
 }
 
-// GetRandomWordByDifficulty retourne un mot aléatoire selon la difficulté
+// GetRandomWordByDifficulty retourne un mot aléatoire de la langue par défaut selon la difficulté
 func GetRandomWordByDifficulty(difficulty string) WordSelection {
-	words, exists := wordCategories[difficulty]
-	if !exists {
-		difficulty = "medium" // Difficulté par défaut
-		words = wordCategories[difficulty]
+	return GetRandomWordByLanguage(DefaultLanguage, difficulty)
+}
+
+// GetRandomWordByLanguage retourne un mot aléatoire d'une langue selon la difficulté
+func GetRandomWordByLanguage(language string, difficulty string) WordSelection {
+	selection, _ := SelectWord(randomSource, language, difficulty, "")
+	return selection
+}
+
+// SelectWord tire un mot du catalogue courant avec la source donnée : avec une
+// même source (même graine) et le même catalogue, le mot est toujours le même.
+// La sélection indique la langue du mot, qui diffère de celle demandée si cette
+// langue n'a plus de mots. ErrUnknownCategory si la catégorie (toutes si vide)
+// n'a aucun mot dans cette langue et cette difficulté.
+func SelectWord(source RandomSource, language string, difficulty string, category string) (WordSelection, error) {
+	words, used := catalogWords(language, difficulty)
+	if category != "" {
+		words, used = nil, language
+		for _, w := range (*wordCatalogs.Load())[language][difficulty] {
+			if w.Category == category {
+				words = append(words, w)
+			}
+		}
+		if len(words) == 0 {
+			return WordSelection{}, ErrUnknownCategory
+		}
 	}
 
-	selectedWord := words[rand.Intn(len(words))]
-	return WordSelection(selectedWord)
+	selectedWord := words[source.Intn(len(words))]
+	return WordSelection{Word: selectedWord.Word, Hint: selectedWord.Hint, Language: used}, nil
 }
 
 // GetHint retourne l'indice pour un mot donné et une difficulté donnée
 func GetHint(word string, difficulty string) string {
-	words, exists := wordCategories[difficulty]
-	if !exists {
-		difficulty = "medium" // Difficulté par défaut
-		words = wordCategories[difficulty]
-	}
+	words, _ := catalogWords(DefaultLanguage, difficulty)
 
 	for _, wordWithHint := range words {
 		if wordWithHint.Word == word {
@@ -106,3 +179,20 @@ func GetHint(word string, difficulty string) string {
 
 	return "No hint available"
 }
+
+// catalogWords retourne les mots d'une langue pour une difficulté, en se
+// rabattant sur la difficulté "medium" puis sur la langue par défaut quand le
+// catalogue courant n'en contient pas, ainsi que la langue de ces mots
+func catalogWords(language string, difficulty string) ([]WordWithHint, string) {
+	catalogs := *wordCatalogs.Load()
+	for _, lang := range []string{language, DefaultLanguage} {
+		for _, d := range []string{difficulty, "medium"} {
+			if words := catalogs[lang][d]; len(words) > 0 {
+				return words, lang
+			}
+		}
+	}
+
+	// Catalogue incomplet (mots désactivés) : revenir aux mots intégrés
+	return englishWords["medium"], "en"
+}
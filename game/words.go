@@ -0,0 +1,592 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Erreurs liées aux mots gérés par les administrateurs
+var (
+	ErrWordNotFound = errors.New("word not found")
+	ErrWordExists   = errors.New("word already exists in this language")
+	ErrInvalidWord  = errors.New("invalid word")
+)
+
+// Actions enregistrées dans le journal des mots
+const (
+	WordCreated  = "create"
+	WordUpdated  = "update"
+	WordEnabled  = "enable"
+	WordDisabled = "disable"
+	WordDeleted  = "delete"
+)
+
+// MaxWordAuditLimit borne le nombre d'entrées du journal renvoyées en une fois
+const MaxWordAuditLimit = 200
+
+// WordEntry est un mot du catalogue géré par les administrateurs. Seuls les
+// mots activés sont proposés dans les nouvelles parties.
+type WordEntry struct {
+	ID         string    `json:"id"`
+	Word       string    `json:"word"`
+	Hint       string    `json:"hint"`
+	Difficulty string    `json:"difficulty"`
+	Category   string    `json:"category"`
+	Language   string    `json:"language"`
+	Tags       []string  `json:"tags"`
+	Enabled    bool      `json:"enabled"`
+	FromFiles  bool      `json:"from_files"` // importé du répertoire de fichiers de mots, qu'il suit
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WordAudit est une entrée du journal des modifications de mots : qui a fait
+// quoi, et l'état du mot avant et après
+type WordAudit struct {
+	ID       string     `json:"id"`
+	WordID   string     `json:"word_id"`
+	Action   string     `json:"action"`
+	UserID   string     `json:"user_id,omitempty"` // vide pour les modifications faites par le serveur
+	UserName string     `json:"user_name"`
+	Before   *WordEntry `json:"before,omitempty"` // absent pour une création
+	After    *WordEntry `json:"after,omitempty"`  // absent pour une suppression
+	At       time.Time  `json:"at"`
+}
+
+// WordEditor identifie l'auteur d'une modification de mots
+type WordEditor struct {
+	UserID   string
+	UserName string
+}
+
+// SystemEditor signe les modifications faites par le serveur (mots intégrés,
+// fichiers de mots)
+var SystemEditor = WordEditor{UserName: "system"}
+
+// WordChange est la création ou la modification d'un mot, enregistrée avec son
+// entrée de journal
+type WordChange struct {
+	Word  *WordEntry
+	Audit WordAudit
+	New   bool // création d'un mot plutôt que modification
+}
+
+// WordFilter sélectionne des mots (champs vides = sans filtre)
+type WordFilter struct {
+	Language   string
+	Difficulty string
+	Category   string
+	Enabled    *bool
+	Search     string // partie du mot, accents et casse ignorés
+}
+
+// WordStore décrit le stockage des mots et de leur journal de modifications.
+// Chaque modification est enregistrée avec son entrée de journal, atomiquement.
+type WordStore interface {
+	// CreateWord enregistre un mot (utils.ErrDuplicateID si son ID ou celui de
+	// l'entrée de journal est déjà utilisé, ErrWordExists si le mot existe déjà
+	// dans sa langue)
+	CreateWord(word *WordEntry, audit WordAudit) error
+	// GetWord récupère un mot par son ID (ErrWordNotFound si absent)
+	GetWord(id string) (*WordEntry, error)
+	// FindWord récupère un mot par sa langue et sa clé WordKey (ErrWordNotFound si absent)
+	FindWord(language, key string) (*WordEntry, error)
+	// ListWords retourne les mots correspondant au filtre, triés par langue puis par mot
+	ListWords(filter WordFilter) ([]WordEntry, error)
+	// UpdateWord remplace un mot existant (mêmes erreurs que CreateWord, et
+	// ErrWordNotFound s'il est absent)
+	UpdateWord(word *WordEntry, audit WordAudit) error
+	// SaveWords enregistre un lot de créations et de modifications dans une seule
+	// transaction : si l'une échoue, rien n'est écrit (mêmes erreurs que
+	// CreateWord et UpdateWord)
+	SaveWords(changes []WordChange) error
+	// DeleteWord supprime un mot (ErrWordNotFound s'il est absent)
+	DeleteWord(id string, audit WordAudit) error
+	// ListWordAudit retourne les dernières entrées du journal, les plus récentes
+	// en premier, éventuellement limitées à un mot
+	ListWordAudit(wordID string, limit int) ([]WordAudit, error)
+	// CountWordAudit retourne le nombre d'entrées du journal. Chaque modification
+	// en ajoute une, dans la même transaction : le nombre change dès qu'un
+	// serveur modifie les mots.
+	CountWordAudit() (int, error)
+}
+
+// WordImportResult résume un import de mots
+type WordImportResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Disabled  int `json:"disabled,omitempty"` // mots retirés du répertoire de fichiers
+}
+
+// GetWord récupère un mot par son ID
+func GetWord(store WordStore, id string) (*WordEntry, error) {
+	return store.GetWord(id)
+}
+
+// ListWords retourne les mots correspondant au filtre
+func ListWords(store WordStore, filter WordFilter) ([]WordEntry, error) {
+	return store.ListWords(filter)
+}
+
+// CreateWord ajoute un mot au catalogue (ErrInvalidWord s'il est invalide,
+// ErrWordExists s'il existe déjà dans sa langue)
+func CreateWord(store WordStore, record WordRecord, editor WordEditor) (*WordEntry, error) {
+	if _, err := normalizeRecord(&record); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWord, err)
+	}
+
+	word, err := createWord(store, record, editor)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshWordCatalog(store)
+	return word, nil
+}
+
+// UpdateWord remplace le mot, l'indice, la difficulté, la langue et les
+// étiquettes d'un mot (et son activation si record.Enabled est renseigné)
+func UpdateWord(store WordStore, id string, record WordRecord, editor WordEditor) (*WordEntry, error) {
+	if _, err := normalizeRecord(&record); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWord, err)
+	}
+
+	existing, err := store.GetWord(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := updateWord(store, existing, record, editor)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshWordCatalog(store)
+	return updated, nil
+}
+
+// SetWordEnabled active ou désactive un mot
+func SetWordEnabled(store WordStore, id string, enabled bool, editor WordEditor) (*WordEntry, error) {
+	existing, err := store.GetWord(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Enabled == enabled {
+		return existing, nil
+	}
+
+	word := *existing
+	word.Enabled = enabled
+	word.UpdatedAt = time.Now().UTC()
+
+	action := WordDisabled
+	if enabled {
+		action = WordEnabled
+	}
+	if err := saveWord(store, &word, newWordAudit(action, editor, existing, &word)); err != nil {
+		return nil, err
+	}
+
+	refreshWordCatalog(store)
+	return &word, nil
+}
+
+// DeleteWord supprime un mot du catalogue
+func DeleteWord(store WordStore, id string, editor WordEditor) error {
+	existing, err := store.GetWord(id)
+	if err != nil {
+		return err
+	}
+
+	audit := newWordAudit(WordDeleted, editor, existing, nil)
+	err = utils.RetryOnDuplicateID(func() error {
+		audit.ID = utils.NewID()
+		return store.DeleteWord(id, audit)
+	})
+	if err != nil {
+		return err
+	}
+
+	refreshWordCatalog(store)
+	return nil
+}
+
+// ImportWords ajoute les mots nouveaux et met à jour ceux qui existent déjà
+// dans leur langue, en une seule transaction. Tous les mots sont validés avant
+// l'écriture (ErrInvalidWord si l'un d'eux est invalide ou en double).
+func ImportWords(store WordStore, records []WordRecord, editor WordEditor) (*WordImportResult, error) {
+	return importWords(store, records, editor, false)
+}
+
+// pendingWordChange est une modification d'import dont les identifiants et
+// l'entrée de journal sont attribués à l'enregistrement
+type pendingWordChange struct {
+	action string
+	before *WordEntry
+	word   *WordEntry
+}
+
+// importWords importe des mots comme ImportWords. Si fromFiles est vrai, les
+// mots viennent du répertoire de fichiers, qui fait foi pour les siens : ils
+// sont réactivés s'ils y reviennent sans "enabled", et les mots qu'il ne
+// contient plus sont désactivés.
+func importWords(store WordStore, records []WordRecord, editor WordEditor, fromFiles bool) (*WordImportResult, error) {
+	sources := make([]string, len(records))
+	for i := range records {
+		sources[i] = fmt.Sprintf("entry %d", i+1)
+	}
+
+	normalized, errs := normalizeRecords(records, sources)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w:\n%w", ErrInvalidWord, errors.Join(errs...))
+	}
+
+	result := &WordImportResult{}
+	var pending []pendingWordChange
+	imported := map[string]bool{}
+	for _, record := range normalized {
+		imported[record.Language+"/"+WordKey(record.Word)] = true
+
+		existing, err := store.FindWord(record.Language, WordKey(record.Word))
+		if errors.Is(err, ErrWordNotFound) {
+			word := newWordEntry(record)
+			word.FromFiles = fromFiles
+			pending = append(pending, pendingWordChange{action: WordCreated, word: word})
+			result.Created++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		word, changed := applyWordRecord(existing, record)
+		if fromFiles && (!word.FromFiles || (record.Enabled == nil && !word.Enabled)) {
+			word.FromFiles = true
+			word.Enabled = word.Enabled || record.Enabled == nil
+			changed = true
+		}
+		if !changed {
+			result.Unchanged++
+			continue
+		}
+		pending = append(pending, pendingWordChange{action: WordUpdated, before: existing, word: word})
+		result.Updated++
+	}
+
+	if fromFiles {
+		words, err := store.ListWords(WordFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range words {
+			existing := &words[i]
+			if !existing.FromFiles || !existing.Enabled || imported[existing.Language+"/"+WordKey(existing.Word)] {
+				continue
+			}
+			word := *existing
+			word.Enabled = false
+			word.UpdatedAt = time.Now().UTC()
+			pending = append(pending, pendingWordChange{action: WordDisabled, before: existing, word: &word})
+			result.Disabled++
+		}
+	}
+
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	// Nouveaux identifiants pour tout le lot en cas de collision
+	err := utils.RetryOnDuplicateID(func() error {
+		changes := make([]WordChange, len(pending))
+		for i, p := range pending {
+			if p.before == nil {
+				p.word.ID = utils.NewID()
+			}
+			audit := newWordAudit(p.action, editor, p.before, p.word)
+			audit.ID = utils.NewID()
+			changes[i] = WordChange{Word: p.word, Audit: audit, New: p.before == nil}
+		}
+		return store.SaveWords(changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshWordCatalog(store)
+	return result, nil
+}
+
+// ExportWords retourne les mots correspondant au filtre sous la forme lue par
+// ImportWords et les fichiers de mots
+func ExportWords(store WordStore, filter WordFilter) ([]WordRecord, error) {
+	words, err := store.ListWords(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]WordRecord, len(words))
+	for i, word := range words {
+		enabled := word.Enabled
+		records[i] = WordRecord{
+			Word:       word.Word,
+			Hint:       word.Hint,
+			Difficulty: word.Difficulty,
+			Category:   word.Category,
+			Tags:       word.Tags,
+			Language:   word.Language,
+			Enabled:    &enabled,
+		}
+	}
+	return records, nil
+}
+
+// ImportWordDir valide les fichiers de mots d'un répertoire puis les importe dans
+// le stockage, en une seule transaction. Tout est rejeté si un fichier est
+// invalide. Les mots importés d'un précédent chargement et retirés des fichiers
+// sont désactivés ; ceux créés par les administrateurs ne sont pas touchés.
+// Retourne le nombre de mots ajoutés, modifiés ou désactivés.
+func ImportWordDir(store WordStore, dir string) (int, error) {
+	records, sources, err := readWordRecordsDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	if _, _, err := buildCatalog(records, sources); err != nil {
+		return 0, err
+	}
+
+	result, err := importWords(store, records, SystemEditor, true)
+	if err != nil {
+		return 0, err
+	}
+	return result.Created + result.Updated + result.Disabled, nil
+}
+
+// ListWordAudit retourne les dernières modifications de mots, les plus récentes
+// en premier (toutes si wordID est vide)
+func ListWordAudit(store WordStore, wordID string, limit int) ([]WordAudit, error) {
+	if limit <= 0 || limit > MaxWordAuditLimit {
+		limit = MaxWordAuditLimit
+	}
+	return store.ListWordAudit(wordID, limit)
+}
+
+// InitWordStore remplit un stockage de mots vide avec le catalogue courant (mots
+// intégrés ou fichiers chargés) puis construit le catalogue des parties à partir
+// des mots activés du stockage
+func InitWordStore(store WordStore) error {
+	words, err := store.ListWords(WordFilter{})
+	if err != nil {
+		return err
+	}
+
+	if len(words) == 0 {
+		var records []WordRecord
+		for language, catalog := range *wordCatalogs.Load() {
+			for difficulty, list := range catalog {
+				for _, w := range list {
+					records = append(records, WordRecord{
+						Word: w.Word, Hint: w.Hint, Difficulty: difficulty, Category: w.Category, Tags: w.Tags,
+						Language: language,
+					})
+				}
+			}
+		}
+		sort.Slice(records, func(i, j int) bool {
+			if records[i].Language != records[j].Language {
+				return records[i].Language < records[j].Language
+			}
+			return records[i].Word < records[j].Word
+		})
+
+		result, err := ImportWords(store, records, SystemEditor)
+		if err != nil {
+			return err
+		}
+		log.Printf("seeded word store with %d words", result.Created)
+	}
+
+	return RefreshWordCatalog(store)
+}
+
+// RefreshWordCatalog reconstruit le catalogue des parties à partir des mots
+// activés du stockage et le remplace d'un bloc. Sans aucun mot activé, le
+// catalogue courant est conservé.
+func RefreshWordCatalog(store WordStore) error {
+	enabled := true
+	words, err := store.ListWords(WordFilter{Enabled: &enabled})
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		log.Printf("no enabled words, keeping the current word lists")
+		return nil
+	}
+
+	catalog := wordCatalog{}
+	for _, w := range words {
+		if catalog[w.Language] == nil {
+			catalog[w.Language] = map[string][]WordWithHint{}
+		}
+		catalog[w.Language][w.Difficulty] = append(catalog[w.Language][w.Difficulty],
+			WordWithHint{Word: w.Word, Hint: w.Hint, Category: w.Category, Tags: w.Tags})
+	}
+
+	wordCatalogs.Store(&catalog)
+	return nil
+}
+
+// StartWordCatalogSync reconstruit périodiquement le catalogue des parties quand
+// les mots du stockage ont changé, y compris par un autre serveur partageant la
+// base, une première fois au premier intervalle. La fonction retournée arrête
+// la synchronisation.
+func StartWordCatalogSync(store WordStore, interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	// Le nombre d'entrées du journal est lu avant les mots : une modification
+	// faite entre les deux sera vue au tour suivant
+	seen := -1
+	check := func() {
+		count, err := store.CountWordAudit()
+		if err != nil {
+			log.Printf("checking word changes: %v", err)
+			return
+		}
+		if count == seen {
+			return
+		}
+		if err := RefreshWordCatalog(store); err != nil {
+			log.Printf("refreshing word catalog: %v", err)
+			return
+		}
+		seen = count
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				check()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// refreshWordCatalog applique une modification au catalogue des parties ; une
+// erreur est seulement journalisée car la modification elle-même a réussi
+func refreshWordCatalog(store WordStore) {
+	if err := RefreshWordCatalog(store); err != nil {
+		log.Printf("refreshing word catalog: %v", err)
+	}
+}
+
+// createWord enregistre un mot déjà normalisé
+func createWord(store WordStore, record WordRecord, editor WordEditor) (*WordEntry, error) {
+	word := newWordEntry(record)
+	err := utils.RetryOnDuplicateID(func() error {
+		word.ID = utils.NewID()
+		audit := newWordAudit(WordCreated, editor, nil, word)
+		audit.ID = utils.NewID()
+		return store.CreateWord(word, audit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return word, nil
+}
+
+// newWordEntry prépare un mot à créer à partir d'un mot normalisé (ID attribué à l'enregistrement)
+func newWordEntry(record WordRecord) *WordEntry {
+	now := time.Now().UTC()
+	return &WordEntry{
+		Word:       record.Word,
+		Hint:       record.Hint,
+		Difficulty: record.Difficulty,
+		Category:   record.Category,
+		Language:   record.Language,
+		Tags:       record.Tags,
+		Enabled:    record.Enabled == nil || *record.Enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// updateWord applique un mot normalisé à un mot existant. existing est retourné
+// tel quel si rien ne change.
+func updateWord(store WordStore, existing *WordEntry, record WordRecord, editor WordEditor) (*WordEntry, error) {
+	word, changed := applyWordRecord(existing, record)
+	if !changed {
+		return existing, nil
+	}
+
+	if err := saveWord(store, word, newWordAudit(WordUpdated, editor, existing, word)); err != nil {
+		return nil, err
+	}
+	return word, nil
+}
+
+// applyWordRecord retourne une copie de existing modifiée par un mot normalisé
+// et indique si elle diffère
+func applyWordRecord(existing *WordEntry, record WordRecord) (*WordEntry, bool) {
+	word := *existing
+	word.Word = record.Word
+	word.Hint = record.Hint
+	word.Difficulty = record.Difficulty
+	word.Category = record.Category
+	word.Language = record.Language
+	word.Tags = record.Tags
+	if record.Enabled != nil {
+		word.Enabled = *record.Enabled
+	}
+
+	if word.Word == existing.Word && word.Hint == existing.Hint && word.Difficulty == existing.Difficulty &&
+		word.Category == existing.Category && word.Language == existing.Language && slices.Equal(word.Tags, existing.Tags) && word.Enabled == existing.Enabled {
+		return &word, false
+	}
+
+	word.UpdatedAt = time.Now().UTC()
+	return &word, true
+}
+
+// saveWord enregistre un mot modifié et son entrée de journal
+func saveWord(store WordStore, word *WordEntry, audit WordAudit) error {
+	return utils.RetryOnDuplicateID(func() error {
+		audit.ID = utils.NewID()
+		return store.UpdateWord(word, audit)
+	})
+}
+
+// newWordAudit prépare l'entrée de journal d'une modification (ID attribué à l'enregistrement)
+func newWordAudit(action string, editor WordEditor, before, after *WordEntry) WordAudit {
+	audit := WordAudit{
+		Action:   action,
+		UserID:   editor.UserID,
+		UserName: editor.UserName,
+		At:       time.Now().UTC(),
+	}
+	if before != nil {
+		c := *before
+		audit.Before = &c
+		audit.WordID = before.ID
+	}
+	if after != nil {
+		c := *after
+		audit.After = &c
+		audit.WordID = after.ID
+	}
+	return audit
+}
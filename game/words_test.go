@@ -0,0 +1,128 @@
+package game_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+)
+
+// TestImportWordDir vérifie que le répertoire de fichiers fait foi pour ses mots :
+// ceux qui en sont retirés sont désactivés, et réactivés s'ils y reviennent,
+// sans toucher aux mots créés par les administrateurs
+func TestImportWordDir(t *testing.T) {
+	t.Cleanup(game.ResetWordCatalog)
+	store := memory.New()
+	dir := t.TempDir()
+	// Chaque langue des fichiers doit avoir des mots de chaque difficulté
+	others := `{"word": "JOYSTICK", "hint": "Stick", "difficulty": "medium", "language": "en"},
+		{"word": "CARTRIDGE", "hint": "Game", "difficulty": "hard", "language": "en"}`
+	writeWords := func(words string) {
+		t.Helper()
+		content := "[" + others + "," + words + "]"
+		if err := os.WriteFile(filepath.Join(dir, "words.json"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	importDir := func(want int) {
+		t.Helper()
+		count, err := game.ImportWordDir(store, dir)
+		if err != nil {
+			t.Fatalf("ImportWordDir: %v", err)
+		}
+		if count != want {
+			t.Errorf("ImportWordDir = %d changed words, want %d", count, want)
+		}
+	}
+	checkEnabled := func(word string, want bool) {
+		t.Helper()
+		w, err := store.FindWord("en", game.WordKey(word))
+		if err != nil {
+			t.Fatalf("FindWord(%s): %v", word, err)
+		}
+		if w.Enabled != want {
+			t.Errorf("%s enabled = %v, want %v", word, w.Enabled, want)
+		}
+	}
+
+	writeWords(`{"word": "PIXEL", "hint": "Dot", "difficulty": "easy", "language": "en"},
+		{"word": "SPRITE", "hint": "Image", "difficulty": "easy", "language": "en"}`)
+	importDir(4)
+
+	_, err := game.ImportWords(store, []game.WordRecord{
+		{Word: "ARCADE", Hint: "Hall", Difficulty: "easy", Language: "en"},
+	}, game.SystemEditor)
+	if err != nil {
+		t.Fatalf("ImportWords: %v", err)
+	}
+
+	writeWords(`{"word": "PIXEL", "hint": "Dot", "difficulty": "easy", "language": "en"}`)
+	importDir(1)
+	checkEnabled("PIXEL", true)
+	checkEnabled("SPRITE", false)
+	checkEnabled("ARCADE", true)
+	importDir(0)
+
+	writeWords(`{"word": "PIXEL", "hint": "Dot", "difficulty": "easy", "language": "en"},
+		{"word": "SPRITE", "hint": "Image", "difficulty": "easy", "language": "en"}`)
+	importDir(1)
+	checkEnabled("SPRITE", true)
+}
+
+// TestWordCatalogSync vérifie qu'un mot désactivé par un autre serveur, sans
+// passer par ce processus, disparaît du catalogue des parties
+func TestWordCatalogSync(t *testing.T) {
+	t.Cleanup(game.ResetWordCatalog)
+	store := memory.New()
+	_, err := game.ImportWords(store, []game.WordRecord{
+		{Word: "PIXEL", Hint: "Dot", Difficulty: "easy", Language: "en"},
+		{Word: "SPRITE", Hint: "Image", Difficulty: "easy", Language: "en"},
+	}, game.SystemEditor)
+	if err != nil {
+		t.Fatalf("ImportWords: %v", err)
+	}
+
+	stop := game.StartWordCatalogSync(store, 5*time.Millisecond)
+	defer stop()
+
+	// L'autre serveur écrit directement dans le stockage partagé
+	sprite, err := store.FindWord("en", game.WordKey("SPRITE"))
+	if err != nil {
+		t.Fatalf("FindWord: %v", err)
+	}
+	disabled := *sprite
+	disabled.Enabled = false
+	audit := game.WordAudit{ID: "A1", WordID: sprite.ID, Action: game.WordDisabled, UserName: "other", Before: sprite, After: &disabled, At: time.Now()}
+	if err := store.UpdateWord(&disabled, audit); err != nil {
+		t.Fatalf("UpdateWord: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		words := drawWords(t, 20)
+		if !words["SPRITE"] {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("SPRITE is still drawn after being disabled by another server: %v", words)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// drawWords tire n mots anglais faciles et retourne ceux qui sont sortis
+func drawWords(t *testing.T, n int) map[string]bool {
+	t.Helper()
+	words := map[string]bool{}
+	for i := range n {
+		selection, err := game.SelectWord(game.NewSeededSource(int64(i)), "en", "easy", "")
+		if err != nil {
+			t.Fatalf("SelectWord: %v", err)
+		}
+		words[selection.Word] = true
+	}
+	return words
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/N95Ryan/8bit-hangman-back/auth"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Clés du contexte Gin renseignées par RequireAuth
+const (
+	userIDKey    = "userID"
+	sessionIDKey = "sessionID"
+	rolesKey     = "roles"
+)
+
+// RequireAuth vérifie le header "Authorization: Bearer <token>" (ou le
+// sous-protocole "bearer" d'une WebSocket) et place l'ID de l'utilisateur
+// authentifié dans le contexte. Si les JWT sont activés, ils sont vérifiés par
+// leur signature sans consulter le stockage.
+func (h *Handler) RequireAuth(c *gin.Context) {
+	token, ok := bearerToken(c.GetHeader("Authorization"))
+	if !ok && c.GetHeader("Authorization") == "" {
+		token, ok = socketBearerToken(c.Request)
+	}
+	if !ok {
+		abortUnauthorized(c, "Missing bearer token")
+		return
+	}
+
+	if h.JWT != nil && auth.LooksLikeJWT(token) {
+		claims, err := h.JWT.Verify(token)
+		if err != nil {
+			abortUnauthorized(c, "Invalid or expired token")
+			return
+		}
+
+		c.Set(userIDKey, claims.Subject)
+		c.Set(sessionIDKey, claims.SessionID)
+		c.Set(rolesKey, claims.Roles)
+		c.Next()
+		return
+	}
+
+	session, err := models.ValidateToken(h.Users, token)
+	if errors.Is(err, models.ErrInvalidToken) || errors.Is(err, models.ErrTokenExpired) {
+		abortUnauthorized(c, "Invalid or expired token")
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		c.Abort()
+		return
+	}
+
+	c.Set(userIDKey, session.UserID)
+	c.Set(sessionIDKey, session.ID)
+	c.Next()
+}
+
+// OptionalAuth authentifie la requête si elle porte un header Authorization (ou
+// le sous-protocole "bearer" d'une WebSocket) et la laisse passer anonymement
+// sinon. Un token invalide est tout de même refusé.
+func (h *Handler) OptionalAuth(c *gin.Context) {
+	if _, ok := socketBearerToken(c.Request); !ok && c.GetHeader("Authorization") == "" {
+		c.Next()
+		return
+	}
+
+	h.RequireAuth(c)
+}
+
+// RequireRole refuse l'accès aux utilisateurs qui n'ont pas le rôle donné.
+// Il doit être placé après RequireAuth.
+func (h *Handler) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, err := h.hasRole(c, role)
+		if err != nil {
+			internalError(c, err)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasRole indique si l'utilisateur authentifié a le rôle donné (faux pour une
+// requête anonyme)
+func (h *Handler) hasRole(c *gin.Context, role string) (bool, error) {
+	// Les rôles d'un JWT sont dans ses claims, ceux d'un token opaque dans le stockage
+	roles, fromToken := c.Get(rolesKey)
+	if !fromToken {
+		if currentUserID(c) == "" {
+			return false, nil
+		}
+		user, err := models.GetUser(h.Users, currentUserID(c))
+		if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+			return false, err
+		}
+		if user != nil {
+			roles = user.Roles
+		}
+	}
+
+	userRoles, _ := roles.([]string)
+	return utils.Contains(userRoles, role), nil
+}
+
+// currentUserID retourne l'ID de l'utilisateur authentifié par RequireAuth
+// (vide pour une requête anonyme acceptée par OptionalAuth)
+func currentUserID(c *gin.Context) string {
+	return c.GetString(userIDKey)
+}
+
+// currentSessionID retourne l'ID de la session utilisée par la requête
+func currentSessionID(c *gin.Context) string {
+	return c.GetString(sessionIDKey)
+}
+
+// bearerToken extrait le token d'un header Authorization de type Bearer
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// abortUnauthorized interrompt la requête avec une réponse 401
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+}
+
+// accessToken retourne le token d'accès à remettre au client : un JWT signé si
+// les JWT sont activés, sinon le token opaque de la session
+func (h *Handler) accessToken(user *models.User, session *models.Session, tokens models.TokenPair) (string, error) {
+	if h.JWT == nil {
+		return tokens.AccessToken, nil
+	}
+	return h.JWT.Issue(user, session.ID, tokens.AccessExpiresAt)
+}
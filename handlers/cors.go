@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS autorise les pages servies par les origines de AllowedOrigins à appeler
+// l'API depuis un navigateur et répond aux requêtes de pré-vérification
+func (h *Handler) CORS(c *gin.Context) {
+	if len(h.AllowedOrigins) == 0 {
+		c.Next()
+		return
+	}
+
+	// La réponse dépend de l'origine : les caches ne doivent pas la partager
+	c.Header("Vary", "Origin")
+	origin := c.GetHeader("Origin")
+	if !slices.Contains(h.AllowedOrigins, origin) {
+		c.Next()
+		return
+	}
+
+	c.Header("Access-Control-Allow-Origin", origin)
+	c.Header("Access-Control-Expose-Headers", "Content-Disposition, WWW-Authenticate")
+	if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE")
+		c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		c.Header("Access-Control-Max-Age", "600")
+		c.AbortWithStatus(http.StatusNoContent)
+		return
+	}
+
+	c.Next()
+}
+
+// allowedOrigin indique si une requête vient de la même origine que l'API,
+// d'une origine de AllowedOrigins ou d'un client qui n'est pas un navigateur
+// (sans header Origin)
+func (h *Handler) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(h.AllowedOrigins, origin) {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
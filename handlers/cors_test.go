@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const allowedOrigin = "https://hangman.example.com"
+
+// TestCORS vérifie que seules les origines autorisées reçoivent les headers
+// CORS, pré-vérification comprise
+func TestCORS(t *testing.T) {
+	srv, store := newTestServer(t, allowedOrigin)
+	g := newTestGame(t, store, "PIXEL")
+	url := srv.URL + "/api/games/" + g.ID
+
+	preflight := http.Header{"Origin": {allowedOrigin}, "Access-Control-Request-Method": {"POST"}}
+	resp := doRequest(t, http.MethodOptions, url+"/guess", nil, preflight)
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != allowedOrigin ||
+		!strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Errorf("preflight from an allowed origin: %d with headers %v, want 204 allowing the origin and Authorization", resp.StatusCode, resp.Header)
+	}
+
+	resp = doRequest(t, http.MethodGet, url, nil, http.Header{"Origin": {allowedOrigin}})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != allowedOrigin || resp.Header.Get("Vary") != "Origin" {
+		t.Errorf("request from an allowed origin: %d with headers %v, want 200 allowing the origin", resp.StatusCode, resp.Header)
+	}
+
+	for _, origin := range []string{"https://evil.example.com", "https://hangman.example.com.evil.com", "http://hangman.example.com"} {
+		preflight.Set("Origin", origin)
+		resp = doRequest(t, http.MethodOptions, url+"/guess", nil, preflight)
+		if resp.StatusCode == http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("preflight from %s: %d allowing %q, want no CORS headers", origin, resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+		}
+		resp = doRequest(t, http.MethodGet, url, nil, http.Header{"Origin": {origin}})
+		if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("request from %s allowed %q, want no CORS headers", origin, resp.Header.Get("Access-Control-Allow-Origin"))
+		}
+	}
+}
+
+// TestSocketOrigins vérifie que la WebSocket d'une partie n'est ouverte qu'aux
+// pages de l'API, des origines autorisées et aux clients sans header Origin
+func TestSocketOrigins(t *testing.T) {
+	srv, store := newTestServer(t, allowedOrigin)
+	g := newTestGame(t, store, "PIXEL")
+
+	tests := []struct {
+		origin string
+		allow  bool
+	}{
+		{"", true},
+		{allowedOrigin, true},
+		{srv.URL, true},
+		{"https://evil.example.com", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.origin != "" {
+			header.Set("Origin", tt.origin)
+		}
+		conn, resp, err := dialGame(srv, g.ID, header)
+		switch {
+		case tt.allow && err != nil:
+			t.Errorf("socket from %q: %v, want it open", tt.origin, err)
+		case !tt.allow && (err == nil || resp == nil || resp.StatusCode != http.StatusForbidden):
+			t.Errorf("socket from %q: %v, want 403", tt.origin, err)
+		}
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
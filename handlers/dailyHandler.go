@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/gin-gonic/gin"
+)
+
+// DailyRequest choisit la langue du défi quotidien
+type DailyRequest struct {
+	Language string `form:"language"` // par défaut selon Accept-Language
+}
+
+// DailyLeaderboardRequest regroupe les filtres et la pagination du classement d'un défi quotidien
+type DailyLeaderboardRequest struct {
+	Day      string `form:"day" binding:"omitempty,datetime=2006-01-02"` // aujourd'hui par défaut
+	Language string `form:"language"`
+	Offset   int    `form:"offset" binding:"min=0"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// GetDaily retourne la partie du joueur pour le défi du jour, créée à sa
+// première demande, et son résumé en émojis une fois terminée
+func (h *Handler) GetDaily(c *gin.Context) {
+	var req DailyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	language := req.Language
+	if language == "" {
+		language = game.MatchLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	now := time.Now()
+	challenge, err := game.GetDailyChallenge(h.Daily, game.DailyDay(now), language)
+	if errors.Is(err, game.ErrUnsupportedLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	g, err := game.PlayDaily(h.Games, h.Daily, challenge, currentUserID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	_, nextAt, _ := game.PeriodRange(game.PeriodDaily, now)
+	response := gin.H{
+		"day":        challenge.Day,
+		"language":   challenge.Language,
+		"difficulty": challenge.Difficulty,
+		"next_at":    nextAt,
+		"game":       gameState(g),
+	}
+	if g.IsOver() {
+		response["summary"] = game.DailySummary(g)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDailyLeaderboard récupère une page du classement d'un défi quotidien
+func (h *Handler) GetDailyLeaderboard(c *gin.Context) {
+	var req DailyLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Day == "" {
+		req.Day = game.DailyDay(time.Now())
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	page, err := game.QueryLeaderboard(h.Leaderboard, game.LeaderboardQuery{
+		Daily:    req.Day,
+		Language: req.Language,
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"day": req.Day, "leaderboard": page})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/N95Ryan/8bit-hangman-back/game"
@@ -11,134 +12,340 @@ import (
 // Structures pour les requêtes
 type CreateGameRequest struct {
 	PlayerName string `json:"player_name" binding:"required,min=3,max=50"`
-	Difficulty string `json:"difficulty"` // "easy", "medium", "hard"
+	Difficulty string `json:"difficulty"`                                     // "easy", "medium", "hard"
+	Share      bool   `json:"share"`                                          // génère un code de partage court
+	Matching   string `json:"matching" binding:"omitempty,oneof=fold strict"` // accents ignorés ou non
+	Language   string `json:"language"`                                       // "en", "fr" (par défaut selon Accept-Language)
+	Category   string `json:"category" binding:"max=30"`                      // "consoles", "characters"... (toutes par défaut)
+	Seed       *int64 `json:"seed"`                                           // graine du tirage (administrateurs ou -allow-game-seeds)
 }
 
+// GuessRequest propose soit une lettre, soit le mot (ou la phrase) entier
 type GuessRequest struct {
-	Letter string `json:"letter" binding:"required,len=1"`
+	Letter string `json:"letter" binding:"required_without=Word,excluded_with=Word,omitempty,max=8"` // une lettre, vérifiée après normalisation
+	Word   string `json:"word" binding:"omitempty,max=100"`
 }
 
 type GetHintRequest struct {
 	GameID string `json:"game_id" binding:"required"`
 }
 
-// CreateGame crée une nouvelle partie
-func CreateGame(c *gin.Context) {
+// CreateGame crée une nouvelle partie. Si la requête est authentifiée, la
+// partie est rattachée au joueur qui pourra seul en soumettre le score.
+func (h *Handler) CreateGame(c *gin.Context) {
 	var req CreateGameRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Créer une nouvelle partie avec la difficulté spécifiée
-	var newGame *game.Game
-	if req.Difficulty != "" {
-		newGame = game.NewGameWithDifficulty(req.Difficulty)
-	} else {
-		newGame = game.NewGame() // Utilise la difficulté par défaut (medium)
+	// Sans langue explicite, suivre les préférences du navigateur
+	language := req.Language
+	if language == "" {
+		language = game.MatchLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	canSeed, err := h.canSeed(c)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if req.Seed != nil && !canSeed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seeded games are reserved to administrators"})
+		return
+	}
+
+	// Créer une nouvelle partie avec la difficulté spécifiée (medium par défaut)
+	newGame, err := game.NewGameWithOptions(h.Games, game.GameOptions{
+		Difficulty: req.Difficulty,
+		Share:      req.Share,
+		PlayerID:   currentUserID(c),
+		Matching:   req.Matching,
+		Language:   language,
+		Category:   req.Category,
+		Seed:       req.Seed,
+	})
+	if errors.Is(err, game.ErrUnsupportedLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+	if errors.Is(err, game.ErrUnknownCategory) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No words in this category for this language and difficulty"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"id":         newGame.ID,
 		"word":       newGame.GetMaskedWord(),
 		"remaining":  newGame.Remaining,
 		"status":     newGame.Status,
 		"difficulty": newGame.Difficulty,
-	})
+		"share_code": newGame.ShareCode,
+		"matching":   newGame.Matching,
+		"language":   newGame.Language,
+		"category":   newGame.Category,
+	}
+	// La graine permettrait de deviner le mot : seuls ceux qui peuvent la fixer la voient
+	if canSeed {
+		response["seed"] = newGame.Seed
+	}
+	c.JSON(http.StatusCreated, response)
 }
 
-// GetGame récupère l'état d'une partie
-func GetGame(c *gin.Context) {
+// canSeed indique si l'utilisateur peut fixer la graine d'une partie ou d'une
+// course, et donc la voir. Elle permet de la rejouer : réservé aux tests et
+// aux administrateurs.
+func (h *Handler) canSeed(c *gin.Context) (bool, error) {
+	if h.AllowSeeds {
+		return true, nil
+	}
+	return h.hasRole(c, models.RoleAdmin)
+}
+
+// GetGame récupère l'état d'une partie du joueur authentifié
+func (h *Handler) GetGame(c *gin.Context) {
 	id := c.Param("id")
 
-	gameInstance, exists := game.GetGame(id)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	gameInstance, ok := h.loadOwnGame(c, id)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":         gameInstance.ID,
-		"word":       gameInstance.GetMaskedWord(),
-		"guesses":    gameInstance.Guesses,
-		"remaining":  gameInstance.Remaining,
-		"status":     gameInstance.Status,
-		"score":      gameInstance.Score,
-		"difficulty": gameInstance.Difficulty,
-		"hint":       gameInstance.Hint,
-	})
+	c.JSON(http.StatusOK, gameState(gameInstance))
 }
 
-// SubmitGuess soumet une lettre pour une partie
-func SubmitGuess(c *gin.Context) {
-	id := c.Param("id")
-
-	gameInstance, exists := game.GetGame(id)
-	if !exists {
+// GetSharedGame récupère l'état d'une partie à partir de son code de partage
+func (h *Handler) GetSharedGame(c *gin.Context) {
+	gameInstance, err := game.GetGameByShareCode(h.Games, c.Param("code"))
+	if errors.Is(err, game.ErrGameNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
 		return
 	}
-
-	if gameInstance.Status != "in_progress" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Game is already completed"})
+	if err != nil {
+		internalError(c, err)
 		return
 	}
 
+	c.JSON(http.StatusOK, gameState(gameInstance))
+}
+
+// gameState construit la représentation publique d'une partie (mot masqué)
+func gameState(g *game.Game) gin.H {
+	return gin.H{
+		"id":           g.ID,
+		"word":         g.GetMaskedWord(),
+		"guesses":      g.Guesses,
+		"word_guesses": g.WordGuesses,
+		"remaining":    g.Remaining,
+		"status":       g.Status,
+		"score":        g.Score,
+		"difficulty":   g.Difficulty,
+		"hint":         g.Hint,
+		"share_code":   g.ShareCode,
+		"matching":     g.Matching,
+		"language":     g.Language,
+		"category":     g.Category,
+		"daily":        g.Daily,
+		"match":        g.Match,
+	}
+}
+
+// SubmitGuess soumet une lettre ou le mot entier pour une partie du joueur authentifié
+func (h *Handler) SubmitGuess(c *gin.Context) {
+	id := c.Param("id")
+
 	var req GuessRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	success := gameInstance.MakeGuess(req.Letter)
+	if _, ok := h.loadOwnGame(c, id); !ok {
+		return
+	}
+
+	var gameInstance *game.Game
+	var success bool
+	var err error
+	if req.Word != "" {
+		gameInstance, success, err = game.SubmitWordGuess(h.Games, id, req.Word)
+	} else {
+		gameInstance, success, err = game.SubmitGuess(h.Games, id, req.Letter)
+	}
+	if errors.Is(err, game.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+	if errors.Is(err, game.ErrGameOver) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Game is already completed"})
+		return
+	}
+	if errors.Is(err, game.ErrInvalidLetter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Letter must be a single letter"})
+		return
+	}
+	if errors.Is(err, game.ErrMatchGame) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match games are played through their match"})
+		return
+	}
+	if errors.Is(err, game.ErrGameConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Game was modified concurrently, please retry"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":    success,
-		"word":       gameInstance.GetMaskedWord(),
-		"guesses":    gameInstance.Guesses,
-		"remaining":  gameInstance.Remaining,
-		"status":     gameInstance.Status,
-		"score":      gameInstance.Score,
-		"difficulty": gameInstance.Difficulty,
-		"hint":       gameInstance.Hint,
+		"success":      success,
+		"word":         gameInstance.GetMaskedWord(),
+		"guesses":      gameInstance.Guesses,
+		"word_guesses": gameInstance.WordGuesses,
+		"remaining":    gameInstance.Remaining,
+		"status":       gameInstance.Status,
+		"score":        gameInstance.Score,
+		"difficulty":   gameInstance.Difficulty,
+		"hint":         gameInstance.Hint,
 	})
 }
 
-// AbandonGame abandonne une partie
-func AbandonGame(c *gin.Context) {
+// AbandonGame abandonne une partie du joueur authentifié
+func (h *Handler) AbandonGame(c *gin.Context) {
 	id := c.Param("id")
 
-	if success := game.DeleteGame(id); !success {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	if _, ok := h.loadOwnGame(c, id); !ok {
+		return
+	}
+
+	if err := game.DeleteGame(h.Games, id); err != nil {
+		if errors.Is(err, game.ErrGameNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+			return
+		}
+		if errors.Is(err, game.ErrDailyGame) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Daily challenge games cannot be abandoned"})
+			return
+		}
+		if errors.Is(err, game.ErrMatchGame) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Match games are played through their match"})
+			return
+		}
+		internalError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-// GetLeaderboard récupère le classement
-func GetLeaderboard(c *gin.Context) {
-	// Récupère le paramètre de difficulté s'il existe
-	difficulty := c.Query("difficulty")
+// LeaderboardRequest regroupe les filtres et la pagination du classement
+type LeaderboardRequest struct {
+	Difficulty string `form:"difficulty"`
+	Language   string `form:"language"`
+	Category   string `form:"category"`
+	Period     string `form:"period" binding:"omitempty,oneof=daily weekly monthly all-time"`
+	Mode       string `form:"mode" binding:"omitempty,oneof=all best"` // "best" : une entrée par joueur
+	Offset     int    `form:"offset" binding:"min=0"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
 
-	// Récupère le classement filtré par difficulté si spécifié
-	var leaderboard []game.LeaderboardEntry
-	if difficulty != "" {
-		leaderboard = game.GetLeaderboardByDifficulty(difficulty, 10)
-	} else {
-		leaderboard = game.GetLeaderboard(10) // Limiter à 10 entrées
+// PlayerStandingRequest regroupe les filtres de la position d'un joueur
+type PlayerStandingRequest struct {
+	Difficulty string `form:"difficulty"`
+	Language   string `form:"language"`
+	Category   string `form:"category"`
+	Period     string `form:"period" binding:"omitempty,oneof=daily weekly monthly all-time"`
+	Mode       string `form:"mode" binding:"omitempty,oneof=all best"`
+	Neighbors  *int   `form:"neighbors" binding:"omitempty,min=0,max=10"`
+}
+
+// GetLeaderboard récupère une page du classement, triée et numérotée
+func (h *Handler) GetLeaderboard(c *gin.Context) {
+	var req LeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	page, err := game.QueryLeaderboard(h.Leaderboard, game.LeaderboardQuery{
+		Difficulty:    req.Difficulty,
+		Language:      req.Language,
+		Category:      req.Category,
+		Period:        req.Period,
+		BestPerPlayer: req.Mode == "best",
+		Offset:        req.Offset,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetPlayerStanding récupère la meilleure entrée d'un joueur, son rang, son
+// percentile et les entrées classées juste avant et juste après lui
+func (h *Handler) GetPlayerStanding(c *gin.Context) {
+	var req PlayerStandingRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	neighbors := 1
+	if req.Neighbors != nil {
+		neighbors = *req.Neighbors
+	}
+
+	standing, err := game.GetPlayerStanding(h.Leaderboard, c.Param("id"), game.LeaderboardQuery{
+		Difficulty:    req.Difficulty,
+		Language:      req.Language,
+		Category:      req.Category,
+		Period:        req.Period,
+		BestPerPlayer: req.Mode == "best",
+	}, neighbors)
+	if errors.Is(err, game.ErrPlayerNotRanked) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player has no score on this leaderboard"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, standing)
+}
+
+// CategoriesRequest filtre la liste des catégories
+type CategoriesRequest struct {
+	Language string `form:"language"` // toutes les langues si vide
+}
+
+// ListCategories liste les catégories proposées à la création d'une partie,
+// avec leur nombre de mots par difficulté
+func (h *Handler) ListCategories(c *gin.Context) {
+	var req CategoriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, leaderboard)
+	c.JSON(http.StatusOK, game.Categories(req.Language))
 }
 
-// GetHint récupère l'indice pour une partie
-func GetHint(c *gin.Context) {
+// GetHint récupère l'indice pour une partie du joueur authentifié
+func (h *Handler) GetHint(c *gin.Context) {
 	id := c.Param("id")
 
-	gameInstance, exists := game.GetGame(id)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	gameInstance, ok := h.loadOwnGame(c, id)
+	if !ok {
 		return
 	}
 
@@ -147,11 +354,11 @@ func GetHint(c *gin.Context) {
 	})
 }
 
-// SubmitScore soumet un score au classement
-func SubmitScore(c *gin.Context) {
+// SubmitScore soumet au classement le score d'une partie terminée de
+// l'utilisateur authentifié et met à jour ses statistiques dans la même transaction
+func (h *Handler) SubmitScore(c *gin.Context) {
 	var req struct {
 		GameID string `json:"game_id" binding:"required"`
-		UserID string `json:"user_id" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -159,27 +366,75 @@ func SubmitScore(c *gin.Context) {
 		return
 	}
 
-	gameInstance, exists := game.GetGame(req.GameID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	gameInstance, ok := h.loadGame(c, req.GameID)
+	if !ok {
+		return
+	}
+
+	user, ok := h.loadUser(c, currentUserID(c))
+	if !ok {
 		return
 	}
 
-	user, exists := models.GetUser(req.UserID)
-	if !exists {
+	entry, err := game.SubmitScore(h.Leaderboard, gameInstance, user.ID, user.Name)
+	if errors.Is(err, game.ErrGameInProgress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Game is not finished"})
+		return
+	}
+	if errors.Is(err, game.ErrNotGamePlayer) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Game was not played by this user"})
+		return
+	}
+	if errors.Is(err, game.ErrScoreAlreadySubmitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Score already submitted for this game"})
+		return
+	}
+	if errors.Is(err, game.ErrMatchGame) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match games are played through their match"})
+		return
+	}
+	if errors.Is(err, models.ErrUserNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
 
-	// Ajouter le score au classement
-	game.AddToLeaderboard(
-		user.ID,
-		user.Name,
-		gameInstance.Score,
-		len(gameInstance.Word),
-		gameInstance.Remaining,
-		gameInstance.Difficulty,
-	)
+// loadGame récupère une partie et répond 404 ou 500 en cas d'échec
+func (h *Handler) loadGame(c *gin.Context, id string) (*game.Game, bool) {
+	gameInstance, err := game.GetGame(h.Games, id)
+	if errors.Is(err, game.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return nil, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return gameInstance, true
+}
 
-	c.Status(http.StatusCreated)
+// loadOwnGame charge une partie comme loadGame. Une partie créée par un joueur
+// authentifié n'est accessible qu'à lui (401 sans token, 403 pour un autre
+// joueur) ; une partie anonyme reste jouable sans token par quiconque connaît
+// son ID.
+func (h *Handler) loadOwnGame(c *gin.Context, id string) (*game.Game, bool) {
+	gameInstance, ok := h.loadGame(c, id)
+	if !ok || gameInstance.PlayerID == "" {
+		return gameInstance, ok
+	}
+	if currentUserID(c) == "" {
+		abortUnauthorized(c, "Missing bearer token")
+		return nil, false
+	}
+	if gameInstance.PlayerID != currentUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Game belongs to another player"})
+		return nil, false
+	}
+	return gameInstance, true
 }
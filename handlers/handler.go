@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/N95Ryan/8bit-hangman-back/auth"
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler regroupe les dépendances partagées par les endpoints HTTP
+type Handler struct {
+	Games       game.GameStore
+	Users       models.UserStore
+	Leaderboard game.LeaderboardStore
+	Seasons     game.SeasonStore
+	Words       game.WordStore
+	Daily       game.DailyStore
+	Matches     game.MatchStore
+
+	// JWT émet des tokens d'accès signés à la place des tokens opaques (nil = désactivé)
+	JWT *auth.JWTManager
+
+	// AllowSeeds autorise tous les joueurs à fixer la graine d'une partie
+	// (réservé aux administrateurs sinon), pour les environnements de test
+	AllowSeeds bool
+
+	// AllowedOrigins sont les origines ("https://example.com") des pages
+	// autorisées à appeler l'API et à ouvrir ses WebSockets depuis un
+	// navigateur, en plus de celle de l'API
+	AllowedOrigins []string
+}
+
+// New crée un Handler à partir des stockages fournis
+func New(games game.GameStore, users models.UserStore, leaderboard game.LeaderboardStore, seasons game.SeasonStore, words game.WordStore, daily game.DailyStore, matches game.MatchStore) *Handler {
+	return &Handler{
+		Games:       games,
+		Users:       users,
+		Leaderboard: leaderboard,
+		Seasons:     seasons,
+		Words:       words,
+		Daily:       daily,
+		Matches:     matches,
+	}
+}
+
+// internalError journalise une erreur de stockage et répond 500 sans exposer le détail
+func internalError(c *gin.Context, err error) {
+	log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
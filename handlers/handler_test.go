@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/handlers"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestServer démarre l'API sur un stockage en mémoire, avec les routes
+// exercées par les tests et les origines autorisées données
+func newTestServer(t *testing.T, origins ...string) (*httptest.Server, *memory.Store) {
+	t.Helper()
+	store := memory.New()
+	h := handlers.New(store, store, store, store, store, store, store)
+	h.AllowedOrigins = origins
+
+	r := gin.New()
+	r.Use(h.CORS)
+	r.GET("/api/games/:id", h.OptionalAuth, h.GetGame)
+	r.POST("/api/games/:id/guess", h.OptionalAuth, h.SubmitGuess)
+	r.DELETE("/api/games/:id", h.OptionalAuth, h.AbandonGame)
+	r.GET("/api/games/:id/ws", h.OptionalAuth, h.GameSocket)
+	r.GET("/api/leaderboard/stream", h.StreamLeaderboard)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+// newTestGame crée une partie anonyme au mot imposé
+func newTestGame(t *testing.T, store game.GameStore, word string) *game.Game {
+	t.Helper()
+	g, err := game.NewGameWithOptions(store, game.GameOptions{
+		Matching: game.MatchFold,
+		Word:     &game.WordSelection{Word: word, Hint: "hint"},
+	})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+	return g
+}
+
+// doRequest envoie une requête à l'API, avec body encodé en JSON s'il n'est pas nil
+func doRequest(t *testing.T, method, url string, body any, header http.Header) *http.Response {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, url, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// dialGame ouvre la WebSocket d'une partie
+func dialGame(srv *httptest.Server, id string, header http.Header, protocols ...string) (*websocket.Conn, *http.Response, error) {
+	dialer := websocket.Dialer{Subprotocols: protocols}
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/games/" + id + "/ws"
+	return dialer.Dial(url, header)
+}
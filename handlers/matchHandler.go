@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateMatchRequest regroupe les paramètres d'une course
+type CreateMatchRequest struct {
+	Difficulty string `json:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+	Matching   string `json:"matching" binding:"omitempty,oneof=fold strict"`
+	Language   string `json:"language"` // par défaut selon Accept-Language
+	Category   string `json:"category" binding:"max=30"`
+	Seed       *int64 `json:"seed"` // graine du tirage (administrateurs ou -allow-game-seeds)
+}
+
+// JoinMatchRequest contient le code du salon à rejoindre
+type JoinMatchRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// CreateMatch ouvre le salon d'une course dont le joueur authentifié est l'hôte
+func (h *Handler) CreateMatch(c *gin.Context) {
+	var req CreateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	language := req.Language
+	if language == "" {
+		language = game.MatchLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	canSeed, err := h.canSeed(c)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+	if req.Seed != nil && !canSeed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Seeded matches are reserved to administrators"})
+		return
+	}
+
+	user, ok := h.loadUser(c, currentUserID(c))
+	if !ok {
+		return
+	}
+
+	m, err := game.NewMatch(h.Matches, game.MatchOptions{
+		HostID:     user.ID,
+		HostName:   user.Name,
+		Difficulty: req.Difficulty,
+		Language:   language,
+		Category:   req.Category,
+		Matching:   req.Matching,
+		Seed:       req.Seed,
+	})
+	if errors.Is(err, game.ErrUnsupportedLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+	if errors.Is(err, game.ErrUnknownCategory) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No words in this category for this language and difficulty"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	response, ok := h.matchState(c, m)
+	if !ok {
+		return
+	}
+	// Comme pour une partie, la graine n'est montrée qu'à ceux qui peuvent la fixer
+	if canSeed {
+		response["seed"] = m.Seed
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// JoinMatch ajoute le joueur authentifié au salon d'une course à partir de son code
+func (h *Handler) JoinMatch(c *gin.Context) {
+	var req JoinMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := h.loadUser(c, currentUserID(c))
+	if !ok {
+		return
+	}
+
+	m, err := game.JoinMatch(h.Matches, req.Code, user.ID, user.Name)
+	if !h.checkMatchError(c, err) {
+		return
+	}
+
+	h.respondMatch(c, http.StatusOK, m)
+}
+
+// GetMatch récupère l'état d'une course : l'avancement de chaque joueur, et la
+// partie complète du joueur authentifié s'il y participe
+func (h *Handler) GetMatch(c *gin.Context) {
+	m, err := game.GetMatch(h.Matches, c.Param("id"))
+	if !h.checkMatchError(c, err) {
+		return
+	}
+
+	h.respondMatch(c, http.StatusOK, m)
+}
+
+// StartMatch lance la course après un compte à rebours (hôte uniquement)
+func (h *Handler) StartMatch(c *gin.Context) {
+	m, err := game.StartMatch(h.Games, h.Matches, c.Param("id"), currentUserID(c))
+	if !h.checkMatchError(c, err) {
+		return
+	}
+
+	h.respondMatch(c, http.StatusOK, m)
+}
+
+// LeaveMatch retire le joueur authentifié du salon, ou lui fait abandonner la
+// course si elle a commencé
+func (h *Handler) LeaveMatch(c *gin.Context) {
+	m, err := game.LeaveMatch(h.Games, h.Matches, c.Param("id"), currentUserID(c))
+	if !h.checkMatchError(c, err) {
+		return
+	}
+
+	h.respondMatch(c, http.StatusOK, m)
+}
+
+// SubmitMatchGuess soumet une lettre ou le mot entier dans la partie de course
+// du joueur authentifié
+func (h *Handler) SubmitMatchGuess(c *gin.Context) {
+	var req GuessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m, _, success, err := game.SubmitMatchGuess(h.Games, h.Matches, c.Param("id"), currentUserID(c),
+		game.MatchGuess{Letter: req.Letter, Word: req.Word})
+	if !h.checkMatchError(c, err) {
+		return
+	}
+
+	response, ok := h.matchState(c, m)
+	if !ok {
+		return
+	}
+	response["success"] = success
+	c.JSON(http.StatusOK, response)
+}
+
+// respondMatch répond avec l'état d'une course vu par le joueur authentifié
+func (h *Handler) respondMatch(c *gin.Context, status int, m *game.Match) {
+	response, ok := h.matchState(c, m)
+	if !ok {
+		return
+	}
+	c.JSON(status, response)
+}
+
+// matchState construit la représentation d'une course : les autres joueurs n'y
+// montrent que leur avancement, jamais leurs lettres, et le mot n'est révélé
+// qu'à la fin de la course
+func (h *Handler) matchState(c *gin.Context, m *game.Match) (gin.H, bool) {
+	now := time.Now()
+	status := m.StatusAt(now)
+
+	response := gin.H{
+		"id":         m.ID,
+		"code":       m.Code,
+		"host_id":    m.HostID,
+		"status":     status,
+		"difficulty": m.Difficulty,
+		"language":   m.Language,
+		"category":   m.Category,
+		"matching":   m.Matching,
+		"created_at": m.CreatedAt,
+	}
+	if !m.StartsAt.IsZero() {
+		response["starts_at"] = m.StartsAt
+	}
+	if status == game.MatchFinished {
+		response["winner_id"] = m.WinnerID
+		response["finished_at"] = m.FinishedAt
+		response["word"] = m.Word
+	}
+
+	players := make([]gin.H, len(m.Players))
+	for i, p := range m.Players {
+		player := gin.H{
+			"player_id":   p.PlayerID,
+			"player_name": p.PlayerName,
+			"joined_at":   p.JoinedAt,
+		}
+		if p.Rank > 0 {
+			player["rank"] = p.Rank
+		}
+		if p.GameID != "" {
+			g, err := game.GetGame(h.Games, p.GameID)
+			if err != nil {
+				internalError(c, err)
+				return nil, false
+			}
+			player["progress"] = game.Progress(g)
+			if p.PlayerID == currentUserID(c) {
+				response["game"] = gameState(g)
+			}
+		}
+		players[i] = player
+	}
+	response["players"] = players
+
+	return response, true
+}
+
+// checkMatchError répond à une erreur d'opération sur une course et indique si
+// l'opération a réussi
+func (h *Handler) checkMatchError(c *gin.Context, err error) bool {
+	if errors.Is(err, game.ErrMatchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return false
+	}
+	if errors.Is(err, game.ErrNotMatchHost) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host can start the match"})
+		return false
+	}
+	if errors.Is(err, game.ErrNotMatchPlayer) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a player of this match"})
+		return false
+	}
+	if errors.Is(err, game.ErrMatchStarted) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match has already started"})
+		return false
+	}
+	if errors.Is(err, game.ErrMatchFull) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match is full"})
+		return false
+	}
+	if errors.Is(err, game.ErrMatchNotEnoughPlayers) {
+		c.JSON(http.StatusConflict, gin.H{"error": "At least 2 players are needed to start the match"})
+		return false
+	}
+	if errors.Is(err, game.ErrMatchNotStarted) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match has not started yet"})
+		return false
+	}
+	if errors.Is(err, game.ErrMatchOver) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match is over"})
+		return false
+	}
+	if errors.Is(err, game.ErrGameOver) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Game is already completed"})
+		return false
+	}
+	if errors.Is(err, game.ErrInvalidLetter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Letter must be a single letter"})
+		return false
+	}
+	if errors.Is(err, game.ErrGameConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Game was modified concurrently, please retry"})
+		return false
+	}
+	if errors.Is(err, game.ErrMatchConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match was modified concurrently, please retry"})
+		return false
+	}
+	if err != nil {
+		internalError(c, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/gin-gonic/gin"
+)
+
+// Structures pour les requêtes
+type CreateSeasonRequest struct {
+	Name     string    `json:"name" binding:"required,max=50"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
+
+// SeasonLeaderboardRequest regroupe les filtres et la pagination du classement d'une saison
+type SeasonLeaderboardRequest struct {
+	Difficulty string `form:"difficulty"`
+	Language   string `form:"language"`
+	Category   string `form:"category"`
+	Mode       string `form:"mode" binding:"omitempty,oneof=all best"`
+	Offset     int    `form:"offset" binding:"min=0"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// seasonState construit la réponse JSON décrivant une saison
+func seasonState(s *game.Season) gin.H {
+	state := gin.H{
+		"id":        s.ID,
+		"name":      s.Name,
+		"starts_at": s.StartsAt,
+		"ends_at":   s.EndsAt,
+		"status":    s.Status(time.Now()),
+	}
+	if !s.ArchivedAt.IsZero() {
+		state["archived_at"] = s.ArchivedAt
+	}
+	return state
+}
+
+// ListSeasons liste les saisons, les plus récentes en premier
+func (h *Handler) ListSeasons(c *gin.Context) {
+	seasons, err := game.ListSeasons(h.Seasons)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	result := make([]gin.H, len(seasons))
+	for i := range seasons {
+		result[i] = seasonState(&seasons[i])
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateSeason crée une saison nommée (réservé aux administrateurs)
+func (h *Handler) CreateSeason(c *gin.Context) {
+	var req CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season, err := game.CreateSeason(h.Seasons, req.Name, req.StartsAt, req.EndsAt)
+	if errors.Is(err, game.ErrInvalidSeason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Season must end after it starts"})
+		return
+	}
+	if errors.Is(err, game.ErrSeasonNameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Season name already taken"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, seasonState(season))
+}
+
+// GetSeasonLeaderboard récupère une page du classement d'une saison : le
+// classement final si elle est archivée, le classement en direct sinon
+func (h *Handler) GetSeasonLeaderboard(c *gin.Context) {
+	var req SeasonLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	season, page, err := game.GetSeasonLeaderboard(h.Leaderboard, h.Seasons, c.Param("id"), game.LeaderboardQuery{
+		Difficulty:    req.Difficulty,
+		Language:      req.Language,
+		Category:      req.Category,
+		BestPerPlayer: req.Mode == "best",
+		Offset:        req.Offset,
+		Limit:         req.Limit,
+	})
+	if errors.Is(err, game.ErrSeasonNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"season":  seasonState(season),
+		"entries": page.Entries,
+		"total":   page.Total,
+		"offset":  page.Offset,
+		"limit":   page.Limit,
+	})
+}
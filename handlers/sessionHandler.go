@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Structures pour les requêtes
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshToken échange un token de rafraîchissement contre une nouvelle paire de tokens
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, tokens, err := models.RefreshSession(h.Users, req.RefreshToken)
+	if errors.Is(err, models.ErrInvalidToken) || errors.Is(err, models.ErrTokenExpired) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	// Recharger l'utilisateur pour que le nouveau JWT reflète ses rôles actuels
+	user, ok := h.loadUser(c, session.UserID)
+	if !ok {
+		return
+	}
+
+	accessToken, err := h.accessToken(user, session, tokens)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":              accessToken,
+		"expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+		"session_id":         session.ID,
+	})
+}
+
+// LogoutUser ferme la session utilisée par la requête. Avec les JWT, le token
+// d'accès reste valide jusqu'à son expiration mais ne peut plus être rafraîchi.
+func (h *Handler) LogoutUser(c *gin.Context) {
+	err := models.RevokeSession(h.Users, currentUserID(c), currentSessionID(c))
+	if err != nil && !errors.Is(err, models.ErrSessionNotFound) {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSessions liste les sessions actives de l'utilisateur authentifié
+func (h *Handler) ListSessions(c *gin.Context) {
+	sessions, err := models.ListSessions(h.Users, currentUserID(c))
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	result := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, gin.H{
+			"id":                 session.ID,
+			"created_at":         session.CreatedAt,
+			"refreshed_at":       session.RefreshedAt,
+			"refresh_expires_at": session.RefreshExpiresAt,
+			"user_agent":         session.UserAgent,
+			"current":            session.ID == currentSessionID(c),
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RevokeSession révoque une session de l'utilisateur authentifié
+func (h *Handler) RevokeSession(c *gin.Context) {
+	err := models.RevokeSession(h.Users, currentUserID(c), c.Param("id"))
+	if errors.Is(err, models.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// socketWriteWait borne l'envoi d'un message à un client
+	socketWriteWait = 10 * time.Second
+	// socketPongWait est le délai sans nouvelle d'un client avant sa déconnexion
+	socketPongWait = 60 * time.Second
+	// socketPingPeriod espace les pings, avant l'expiration de socketPongWait
+	socketPingPeriod = socketPongWait * 9 / 10
+	// socketMaxMessageSize borne la taille d'un message reçu
+	socketMaxMessageSize = 512
+	// socketAuthProtocol est le sous-protocole par lequel un navigateur, qui ne
+	// peut pas fixer de header sur une WebSocket, transmet son token :
+	// new WebSocket(url, ["bearer", token])
+	socketAuthProtocol = "bearer"
+)
+
+// socketUpgrader ouvre les WebSockets ; l'origine est vérifiée par GameSocket
+var socketUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	Subprotocols:    []string{socketAuthProtocol},
+}
+
+// liveFields sont les champs de l'état d'une partie envoyés à chaque modification
+var liveFields = []string{"word", "guesses", "word_guesses", "remaining", "status", "score"}
+
+// SocketMessage est un message envoyé par un client sur la WebSocket d'une
+// partie : {"type": "guess", "letter": "E"} ou {"type": "guess", "word": "PAC MAN"}
+type SocketMessage struct {
+	Type string `json:"type"`
+	GuessRequest
+}
+
+// GameSocket ouvre une WebSocket sur une partie. Le client reçoit son état
+// complet, puis les champs modifiés par chaque proposition, d'où qu'elle vienne.
+// La connexion est fermée quand la partie est abandonnée.
+func (h *Handler) GameSocket(c *gin.Context) {
+	id := c.Param("id")
+
+	// S'abonner avant de lire l'état initial pour ne manquer aucune modification
+	updates, cancel := game.SubscribeGame(id)
+	defer cancel()
+
+	gameInstance, ok := h.loadOwnGame(c, id)
+	if !ok {
+		return
+	}
+
+	// Seules les pages de l'API et des origines autorisées ouvrent la WebSocket
+	upgrader := socketUpgrader
+	upgrader.CheckOrigin = h.allowedOrigin
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade a déjà répondu au client
+		return
+	}
+	defer conn.Close()
+
+	replies := make(chan gin.H, 8)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writeGameSocket(conn, gameInstance, updates, replies, quit)
+	}()
+
+	h.readGameSocket(c, conn, id, replies, done)
+
+	// Arrêter l'écriture avant de clore l'abonnement, dont la fermeture
+	// signifierait l'abandon de la partie
+	close(quit)
+	<-done
+}
+
+// socketBearerToken extrait le token transmis par le sous-protocole
+// socketAuthProtocol à l'ouverture d'une WebSocket
+func socketBearerToken(r *http.Request) (string, bool) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		return "", false
+	}
+
+	protocols := websocket.Subprotocols(r)
+	if len(protocols) != 2 || protocols[0] != socketAuthProtocol || protocols[1] == "" {
+		return "", false
+	}
+	return protocols[1], true
+}
+
+// readGameSocket traite les propositions d'un client jusqu'à sa déconnexion ou
+// la fin de l'écriture
+func (h *Handler) readGameSocket(c *gin.Context, conn *websocket.Conn, id string, replies chan<- gin.H, done <-chan struct{}) {
+	conn.SetReadLimit(socketMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(socketPongWait))
+
+		reply := h.handleSocketMessage(c, id, data)
+		select {
+		case replies <- reply:
+		case <-done:
+			return
+		}
+	}
+}
+
+// handleSocketMessage applique le message d'un client et retourne la réponse à
+// lui envoyer. Le nouvel état est diffusé à tous les clients par UpdateGame.
+func (h *Handler) handleSocketMessage(c *gin.Context, id string, data []byte) gin.H {
+	var msg SocketMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return gin.H{"type": "error", "error": "Invalid message"}
+	}
+	if msg.Type != "guess" {
+		return gin.H{"type": "error", "error": "Unknown message type"}
+	}
+	if err := binding.Validator.ValidateStruct(&msg.GuessRequest); err != nil {
+		return gin.H{"type": "error", "error": err.Error()}
+	}
+
+	var success bool
+	var err error
+	if msg.Word != "" {
+		_, success, err = game.SubmitWordGuess(h.Games, id, msg.Word)
+	} else {
+		_, success, err = game.SubmitGuess(h.Games, id, msg.Letter)
+	}
+	if errors.Is(err, game.ErrGameNotFound) {
+		return gin.H{"type": "error", "error": "Game not found"}
+	}
+	if errors.Is(err, game.ErrGameOver) {
+		return gin.H{"type": "error", "error": "Game is already completed"}
+	}
+	if errors.Is(err, game.ErrInvalidLetter) {
+		return gin.H{"type": "error", "error": "Letter must be a single letter"}
+	}
+	if errors.Is(err, game.ErrMatchGame) {
+		return gin.H{"type": "error", "error": "Match games are played through their match"}
+	}
+	if errors.Is(err, game.ErrGameConflict) {
+		return gin.H{"type": "error", "error": "Game was modified concurrently, please retry"}
+	}
+	if err != nil {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		return gin.H{"type": "error", "error": "Internal server error"}
+	}
+
+	return gin.H{"type": "guess", "success": success}
+}
+
+// writeGameSocket envoie au client l'état initial de la partie, les champs
+// modifiés à chaque mise à jour, les réponses à ses messages et des pings
+// réguliers, jusqu'à l'abandon de la partie, une erreur d'écriture ou quit
+func writeGameSocket(conn *websocket.Conn, g *game.Game, updates <-chan *game.Game, replies <-chan gin.H, quit <-chan struct{}) {
+	// Fermer la connexion débloque aussi la lecture
+	defer conn.Close()
+
+	ticker := time.NewTicker(socketPingPeriod)
+	defer ticker.Stop()
+
+	send := func(msg gin.H) error {
+		conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+		return conn.WriteJSON(msg)
+	}
+
+	last := gameState(g)
+	if err := send(gin.H{"type": "state", "game": last}); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case g, open := <-updates:
+			if !open {
+				send(gin.H{"type": "deleted"})
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, "game abandoned"),
+					time.Now().Add(socketWriteWait))
+				return
+			}
+
+			state := gameState(g)
+			changes := gin.H{}
+			for _, field := range liveFields {
+				if !reflect.DeepEqual(state[field], last[field]) {
+					changes[field] = state[field]
+				}
+			}
+			last = state
+			if len(changes) == 0 {
+				continue
+			}
+			if err := send(gin.H{"type": "update", "changes": changes}); err != nil {
+				return
+			}
+		case reply := <-replies:
+			if err := send(reply); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteWait)); err != nil {
+				return
+			}
+		case <-quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/gorilla/websocket"
+)
+
+// readSocket lit le prochain message JSON d'une WebSocket
+func readSocket(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg map[string]any
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("reading the socket: %v", err)
+	}
+	return msg
+}
+
+// checkChanges vérifie qu'un message est une mise à jour portant exactement ces champs
+func checkChanges(t *testing.T, msg map[string]any, want map[string]any) {
+	t.Helper()
+	changes, _ := msg["changes"].(map[string]any)
+	if msg["type"] != "update" || fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("message = %v, want an update of %v", msg, want)
+	}
+}
+
+// TestGameSocket vérifie qu'un client reçoit l'état complet de la partie, puis
+// seulement les champs modifiés par chaque proposition, qu'elle vienne de l'API
+// ou d'une WebSocket, et que la connexion est fermée quand la partie est abandonnée
+func TestGameSocket(t *testing.T) {
+	srv, store := newTestServer(t)
+	g := newTestGame(t, store, "PIXEL")
+
+	player, _, err := dialGame(srv, g.ID, nil)
+	if err != nil {
+		t.Fatalf("opening the socket: %v", err)
+	}
+	defer player.Close()
+	spectator, _, err := dialGame(srv, g.ID, nil)
+	if err != nil {
+		t.Fatalf("opening the socket: %v", err)
+	}
+	defer spectator.Close()
+
+	for _, conn := range []*websocket.Conn{player, spectator} {
+		msg := readSocket(t, conn)
+		state, _ := msg["game"].(map[string]any)
+		if msg["type"] != "state" || state["word"] != "_____" || state["remaining"] != 6.0 || state["hint"] != "hint" {
+			t.Fatalf("first message = %v, want the whole state", msg)
+		}
+	}
+
+	resp := doRequest(t, http.MethodPost, srv.URL+"/api/games/"+g.ID+"/guess", map[string]string{"letter": "p"}, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST guess = %d, want 200", resp.StatusCode)
+	}
+	for _, conn := range []*websocket.Conn{player, spectator} {
+		checkChanges(t, readSocket(t, conn), map[string]any{"guesses": []any{"P"}, "score": 30.0, "word": "P____"})
+	}
+
+	// Le joueur reçoit la réponse à son message et la mise à jour, dans un ordre quelconque
+	if err := player.WriteJSON(map[string]string{"type": "guess", "letter": "z"}); err != nil {
+		t.Fatalf("writing to the socket: %v", err)
+	}
+	received := map[any]map[string]any{}
+	for range 2 {
+		msg := readSocket(t, player)
+		received[msg["type"]] = msg
+	}
+	if reply := received["guess"]; reply == nil || reply["success"] != false {
+		t.Errorf("reply to a wrong letter = %v, want a failed guess", reply)
+	}
+	checkChanges(t, received["update"], map[string]any{"guesses": []any{"P", "Z"}, "remaining": 5.0})
+	checkChanges(t, readSocket(t, spectator), map[string]any{"guesses": []any{"P", "Z"}, "remaining": 5.0})
+
+	for message, want := range map[string]string{
+		`{"type": "guess", "letter": "1"}`:    "Letter must be a single letter",
+		`{"type": "dance"}`:                   "Unknown message type",
+		`not json`:                            "Invalid message",
+		`{"type": "guess", "letter": "ZZZZ"}`: "Letter must be a single letter",
+	} {
+		if err := player.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+			t.Fatalf("writing to the socket: %v", err)
+		}
+		if reply := readSocket(t, player); reply["type"] != "error" || reply["error"] != want {
+			t.Errorf("reply to %s = %v, want the error %q", message, reply, want)
+		}
+	}
+
+	resp = doRequest(t, http.MethodDelete, srv.URL+"/api/games/"+g.ID, nil, nil)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE game = %d, want 204", resp.StatusCode)
+	}
+	for _, conn := range []*websocket.Conn{player, spectator} {
+		if msg := readSocket(t, conn); msg["type"] != "deleted" {
+			t.Errorf("message after abandoning = %v, want deleted", msg)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormalClosure {
+			t.Errorf("read after abandoning = %v, want a normal closure", err)
+		}
+	}
+}
+
+// TestGameSocketAuth vérifie qu'une partie rattachée à un joueur n'ouvre sa
+// WebSocket qu'avec son token, transmis par le sous-protocole "bearer"
+func TestGameSocketAuth(t *testing.T) {
+	srv, store := newTestServer(t)
+	alice, err := models.CreateUser(store, "alice", "password", "alice@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	_, tokens, err := models.CreateSession(store, alice.ID, "test")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	g, err := game.NewGameWithOptions(store, game.GameOptions{PlayerID: alice.ID})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+
+	if _, resp, err := dialGame(srv, g.ID, nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("socket without a token: %v, want 401", err)
+	}
+	if _, resp, err := dialGame(srv, g.ID, nil, "bearer", "wrong"); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("socket with a wrong token: %v, want 401", err)
+	}
+
+	conn, _, err := dialGame(srv, g.ID, nil, "bearer", tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("socket with the player's token: %v", err)
+	}
+	defer conn.Close()
+	if conn.Subprotocol() != "bearer" {
+		t.Errorf("negotiated subprotocol = %q, want bearer", conn.Subprotocol())
+	}
+	if msg := readSocket(t, conn); msg["type"] != "state" {
+		t.Errorf("first message = %v, want the state", msg)
+	}
+}
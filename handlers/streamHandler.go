@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat espace les commentaires qui gardent le flux ouvert à travers
+// les proxys quand le classement ne change pas
+const streamHeartbeat = 30 * time.Second
+
+// LeaderboardStreamRequest filtre le flux du classement
+type LeaderboardStreamRequest struct {
+	Difficulty  []string `form:"difficulty" binding:"dive,oneof=easy medium hard"` // toutes si absent
+	LastEventID string   `form:"last_event_id"`                                    // à défaut du header Last-Event-ID
+}
+
+// StreamLeaderboard diffuse en Server-Sent Events le haut du classement des
+// difficultés demandées : un événement "snapshot" par difficulté à la connexion,
+// puis un événement "update" chaque fois qu'un score y entre. Un client qui se
+// reconnecte avec Last-Event-ID reçoit les événements manqués.
+func (h *Handler) StreamLeaderboard(c *gin.Context) {
+	var req LeaderboardStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	requested := req.Difficulty
+	if len(requested) == 0 {
+		requested = game.Difficulties
+	}
+	var difficulties []string
+	wanted := map[string]bool{}
+	for _, d := range requested {
+		if !wanted[d] {
+			wanted[d] = true
+			difficulties = append(difficulties, d)
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = req.LastEventID
+	}
+
+	sub := game.SubscribeLeaderboard(lastEventID)
+	defer sub.Close()
+
+	// Sans reprise possible, repartir de l'état actuel du classement
+	var events []game.LeaderboardEvent
+	if !sub.Resumed {
+		for _, d := range difficulties {
+			top, err := game.LeaderboardTop(h.Leaderboard, d)
+			if err != nil {
+				internalError(c, err)
+				return
+			}
+			events = append(events, game.LeaderboardEvent{
+				ID: sub.LastID, Type: game.LeaderboardSnapshot, Difficulty: d, Top: top,
+			})
+		}
+	}
+	events = append(events, sub.Missed...)
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	for _, event := range events {
+		if wanted[event.Difficulty] {
+			renderLeaderboardEvent(c, event)
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, open := <-sub.Events:
+			if !open {
+				// Trop de retard : le client se reconnectera avec Last-Event-ID
+				return false
+			}
+			if wanted[event.Difficulty] {
+				renderLeaderboardEvent(c, event)
+			}
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// renderLeaderboardEvent écrit un événement du classement au format SSE
+func renderLeaderboardEvent(c *gin.Context, event game.LeaderboardEvent) {
+	c.Render(-1, sse.Event{
+		Id:    event.ID,
+		Event: event.Type,
+		Data:  event,
+	})
+}
@@ -0,0 +1,168 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+)
+
+// streamEvent est un événement Server-Sent Events lu sur le flux
+type streamEvent struct {
+	ID    string
+	Event string
+	Data  game.LeaderboardEvent
+}
+
+// openStream se connecte au flux du classement et retourne ses événements,
+// jusqu'à l'appel de la fonction de fermeture
+func openStream(t *testing.T, srv *httptest.Server, query string, header http.Header) (<-chan streamEvent, func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/leaderboard/stream"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("opening the stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("opening the stream: %d, want 200", resp.StatusCode)
+	}
+
+	events := make(chan streamEvent, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(events)
+		scanner := bufio.NewScanner(resp.Body)
+		var event streamEvent
+		for scanner.Scan() {
+			field, value, _ := strings.Cut(scanner.Text(), ":")
+			switch field {
+			case "id":
+				event.ID = value
+			case "event":
+				event.Event = value
+			case "data":
+				if err := json.Unmarshal([]byte(value), &event.Data); err != nil {
+					t.Errorf("decoding event data %q: %v", value, err)
+				}
+			case "":
+				if event.Event != "" {
+					events <- event
+				}
+				event = streamEvent{}
+			}
+		}
+	}()
+
+	return events, func() {
+		cancel()
+		resp.Body.Close()
+		<-done
+	}
+}
+
+// nextEvent attend le prochain événement du flux
+func nextEvent(t *testing.T, events <-chan streamEvent) streamEvent {
+	t.Helper()
+	select {
+	case event, open := <-events:
+		if !open {
+			t.Fatal("stream closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event on the stream")
+	}
+	return streamEvent{}
+}
+
+// noEvent vérifie qu'aucun événement n'arrive sur le flux pendant un court instant
+func noEvent(t *testing.T, events <-chan streamEvent) {
+	t.Helper()
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestStreamLeaderboard vérifie que le flux envoie le haut du classement de
+// chaque difficulté demandée, puis ses changements, et qu'un client qui se
+// reconnecte avec Last-Event-ID reçoit les seuls événements manqués
+func TestStreamLeaderboard(t *testing.T) {
+	srv, store := newTestServer(t)
+	add := func(difficulty string, score int) *game.LeaderboardEntry {
+		t.Helper()
+		entry, err := game.AddToLeaderboard(store, game.LeaderboardEntry{
+			PlayerID: "alice", PlayerName: "alice", Score: score, Difficulty: difficulty, Language: "en",
+		})
+		if err != nil {
+			t.Fatalf("AddToLeaderboard: %v", err)
+		}
+		return entry
+	}
+	checkUpdate := func(event streamEvent, entry *game.LeaderboardEntry, rank, top int) {
+		t.Helper()
+		if event.Event != game.LeaderboardUpdate || event.Data.Entry == nil || event.Data.Entry.ID != entry.ID ||
+			event.Data.Entry.Rank != rank || len(event.Data.Top) != top {
+			t.Errorf("event = %+v, want an update adding %s at rank %d of %d", event, entry.ID, rank, top)
+		}
+	}
+
+	first := add("easy", 100)
+
+	events, closeStream := openStream(t, srv, "?difficulty=easy&difficulty=hard", nil)
+	for _, difficulty := range []string{"easy", "hard"} {
+		event := nextEvent(t, events)
+		if event.Event != game.LeaderboardSnapshot || event.Data.Difficulty != difficulty {
+			t.Fatalf("event = %+v, want the %s snapshot", event, difficulty)
+		}
+		if difficulty == "easy" && (len(event.Data.Top) != 1 || event.Data.Top[0].ID != first.ID) {
+			t.Errorf("easy snapshot = %+v, want %s alone", event.Data.Top, first.ID)
+		}
+	}
+
+	add("medium", 500)
+	second := add("easy", 200)
+	received := nextEvent(t, events)
+	checkUpdate(received, second, 1, 2)
+	noEvent(t, events)
+	closeStream()
+
+	// Événements publiés pendant la déconnexion
+	third := add("hard", 300)
+	add("medium", 600)
+	fourth := add("easy", 50)
+
+	events, closeStream = openStream(t, srv, "?difficulty=easy&difficulty=hard", http.Header{"Last-Event-ID": {received.ID}})
+	checkUpdate(nextEvent(t, events), third, 1, 1)
+	checkUpdate(nextEvent(t, events), fourth, 3, 3)
+	noEvent(t, events)
+	closeStream()
+
+	// Le paramètre last_event_id remplace le header pour les clients qui ne peuvent pas le fixer
+	events, closeStream = openStream(t, srv, "?difficulty=easy&last_event_id="+received.ID, nil)
+	checkUpdate(nextEvent(t, events), fourth, 3, 3)
+	noEvent(t, events)
+	closeStream()
+
+	// Un ID inconnu ne permet pas de reprendre : le client repart du haut du classement
+	events, closeStream = openStream(t, srv, "?difficulty=easy", http.Header{"Last-Event-ID": {"unknown-1"}})
+	if event := nextEvent(t, events); event.Event != game.LeaderboardSnapshot || len(event.Data.Top) != 3 {
+		t.Errorf("event after an unknown ID = %+v, want the easy snapshot of 3 entries", event)
+	}
+	closeStream()
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/N95Ryan/8bit-hangman-back/models"
@@ -20,23 +21,21 @@ type LoginRequest struct {
 }
 
 // RegisterUser enregistre un nouvel utilisateur
-func RegisterUser(c *gin.Context) {
+func (h *Handler) RegisterUser(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Vérifier si l'utilisateur existe déjà
-	if models.UserExists(req.Username) {
+	// Créer un nouvel utilisateur (refusé si le nom existe déjà)
+	user, err := models.CreateUser(h.Users, req.Username, req.Password, req.Email)
+	if errors.Is(err, models.ErrUsernameTaken) {
 		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
 		return
 	}
-
-	// Créer un nouvel utilisateur
-	user, err := models.CreateUser(req.Username, req.Password, req.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		internalError(c, err)
 		return
 	}
 
@@ -49,7 +48,7 @@ func RegisterUser(c *gin.Context) {
 }
 
 // LoginUser connecte un utilisateur
-func LoginUser(c *gin.Context) {
+func (h *Handler) LoginUser(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -57,27 +56,47 @@ func LoginUser(c *gin.Context) {
 	}
 
 	// Vérifier les identifiants
-	user, token, err := models.AuthenticateUser(req.Username, req.Password)
-	if err != nil {
+	user, err := models.AuthenticateUser(h.Users, req.Username, req.Password)
+	if errors.Is(err, models.ErrInvalidCredentials) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	// Ouvrir une session
+	session, tokens, err := models.CreateSession(h.Users, user.ID, c.Request.UserAgent())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
 
-	// Retourner le token et les informations utilisateur
+	accessToken, err := h.accessToken(user, session, tokens)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	// Retourner les tokens et les informations utilisateur
 	c.JSON(http.StatusOK, gin.H{
-		"token":    token,
-		"id":       user.ID,
-		"username": user.Name,
+		"token":              accessToken,
+		"expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+		"session_id":         session.ID,
+		"id":                 user.ID,
+		"username":           user.Name,
 	})
 }
 
-// GetUserProfile récupère le profil d'un utilisateur
-func GetUserProfile(c *gin.Context) {
-	userID := c.Param("id")
+// GetUserProfile récupère le profil de l'utilisateur authentifié
+func (h *Handler) GetUserProfile(c *gin.Context) {
+	userID := currentUserID(c)
 
-	user, exists := models.GetUser(userID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, ok := h.loadUser(c, userID)
+	if !ok {
 		return
 	}
 
@@ -85,6 +104,7 @@ func GetUserProfile(c *gin.Context) {
 		"id":       user.ID,
 		"username": user.Name,
 		"email":    user.Email,
+		"roles":    user.Roles,
 		"stats": gin.H{
 			"games_played": user.GamesPlayed,
 			"games_won":    user.GamesWon,
@@ -93,19 +113,19 @@ func GetUserProfile(c *gin.Context) {
 	})
 }
 
-// UpdateUserProfile met à jour le profil d'un utilisateur
-func UpdateUserProfile(c *gin.Context) {
-	userID := c.Param("id")
+// UpdateUserProfile met à jour le profil de l'utilisateur authentifié.
+// Un changement de mot de passe révoque toutes les sessions, y compris la session courante.
+func (h *Handler) UpdateUserProfile(c *gin.Context) {
+	userID := currentUserID(c)
 
-	user, exists := models.GetUser(userID)
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, ok := h.loadUser(c, userID)
+	if !ok {
 		return
 	}
 
 	var req struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" binding:"omitempty,email"`
+		Password string `json:"password" binding:"omitempty,min=6"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -115,24 +135,38 @@ func UpdateUserProfile(c *gin.Context) {
 
 	// Mettre à jour les champs si fournis
 	if req.Email != "" {
-		user.Email = req.Email
+		updated, err := models.UpdateUserEmail(h.Users, userID, req.Email)
+		if err != nil {
+			internalError(c, err)
+			return
+		}
+		user = updated
 	}
 
 	if req.Password != "" {
-		if err := models.UpdateUserPassword(userID, req.Password); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err := models.UpdateUserPassword(h.Users, userID, req.Password); err != nil {
+			internalError(c, err)
 			return
 		}
 	}
 
-	if err := models.UpdateUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"id":       user.ID,
 		"username": user.Name,
 		"email":    user.Email,
 	})
 }
+
+// loadUser récupère un utilisateur et répond 404 ou 500 en cas d'échec
+func (h *Handler) loadUser(c *gin.Context, id string) (*models.User, bool) {
+	user, err := models.GetUser(h.Users, id)
+	if errors.Is(err, models.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	if err != nil {
+		internalError(c, err)
+		return nil, false
+	}
+	return user, true
+}
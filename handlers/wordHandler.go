@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWordImportSize borne la taille d'un fichier de mots importé
+const maxWordImportSize = 1 << 20
+
+// Structures pour les requêtes
+type WordRequest struct {
+	Word       string   `json:"word" binding:"required,max=100"`
+	Hint       string   `json:"hint" binding:"required,max=200"`
+	Difficulty string   `json:"difficulty" binding:"omitempty,oneof=easy medium hard auto"` // classée automatiquement si vide ou "auto"
+	Category   string   `json:"category" binding:"max=30"`
+	Language   string   `json:"language" binding:"required,len=2"`
+	Tags       []string `json:"tags" binding:"max=20,dive,max=30"`
+	Enabled    *bool    `json:"enabled"` // true par défaut à la création, inchangé à la mise à jour
+}
+
+// WordListRequest regroupe les filtres de la liste des mots
+type WordListRequest struct {
+	Language   string `form:"language"`
+	Difficulty string `form:"difficulty"`
+	Category   string `form:"category"`
+	Enabled    *bool  `form:"enabled"`
+	Search     string `form:"q"`
+}
+
+// WordExportRequest choisit le format et les mots exportés
+type WordExportRequest struct {
+	WordListRequest
+	Format string `form:"format" binding:"omitempty,oneof=json yaml csv"`
+}
+
+// WordReportRequest filtre le rapport de classement des mots
+type WordReportRequest struct {
+	WordListRequest
+	All bool `form:"all"` // inclut les mots bien classés
+}
+
+// WordAuditRequest filtre le journal des modifications de mots
+type WordAuditRequest struct {
+	WordID string `form:"word_id"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// record convertit la requête en ligne de fichier de mots
+func (req *WordRequest) record() game.WordRecord {
+	return game.WordRecord{
+		Word:       req.Word,
+		Hint:       req.Hint,
+		Difficulty: req.Difficulty,
+		Category:   req.Category,
+		Tags:       req.Tags,
+		Language:   req.Language,
+		Enabled:    req.Enabled,
+	}
+}
+
+// filter convertit la requête en filtre de mots
+func (req *WordListRequest) filter() game.WordFilter {
+	return game.WordFilter{
+		Language:   req.Language,
+		Difficulty: req.Difficulty,
+		Category:   req.Category,
+		Enabled:    req.Enabled,
+		Search:     req.Search,
+	}
+}
+
+// ListWords liste les mots du catalogue, activés ou non
+func (h *Handler) ListWords(c *gin.Context) {
+	var req WordListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	words, err := game.ListWords(h.Words, req.filter())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, words)
+}
+
+// GetWord récupère un mot du catalogue
+func (h *Handler) GetWord(c *gin.Context) {
+	word, err := game.GetWord(h.Words, c.Param("id"))
+	if errors.Is(err, game.ErrWordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
+
+// CreateWord ajoute un mot au catalogue
+func (h *Handler) CreateWord(c *gin.Context) {
+	var req WordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	editor, ok := h.wordEditor(c)
+	if !ok {
+		return
+	}
+
+	word, err := game.CreateWord(h.Words, req.record(), editor)
+	if !h.checkWordError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, word)
+}
+
+// UpdateWord remplace un mot du catalogue
+func (h *Handler) UpdateWord(c *gin.Context) {
+	var req WordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	editor, ok := h.wordEditor(c)
+	if !ok {
+		return
+	}
+
+	word, err := game.UpdateWord(h.Words, c.Param("id"), req.record(), editor)
+	if !h.checkWordError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
+
+// EnableWord propose à nouveau un mot dans les nouvelles parties
+func (h *Handler) EnableWord(c *gin.Context) {
+	h.setWordEnabled(c, true)
+}
+
+// DisableWord retire un mot des nouvelles parties sans le supprimer
+func (h *Handler) DisableWord(c *gin.Context) {
+	h.setWordEnabled(c, false)
+}
+
+// setWordEnabled active ou désactive le mot de la requête
+func (h *Handler) setWordEnabled(c *gin.Context, enabled bool) {
+	editor, ok := h.wordEditor(c)
+	if !ok {
+		return
+	}
+
+	word, err := game.SetWordEnabled(h.Words, c.Param("id"), enabled, editor)
+	if !h.checkWordError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, word)
+}
+
+// DeleteWord supprime un mot du catalogue
+func (h *Handler) DeleteWord(c *gin.Context) {
+	editor, ok := h.wordEditor(c)
+	if !ok {
+		return
+	}
+
+	err := game.DeleteWord(h.Words, c.Param("id"), editor)
+	if !h.checkWordError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ImportWords ajoute ou met à jour des mots en masse. Le format est donné par
+// le paramètre format ou, à défaut, par le Content-Type (JSON par défaut).
+func (h *Handler) ImportWords(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = wordFormat(c.ContentType())
+	}
+
+	records, err := game.ReadWordRecords(http.MaxBytesReader(c.Writer, c.Request.Body, maxWordImportSize), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	editor, ok := h.wordEditor(c)
+	if !ok {
+		return
+	}
+
+	result, err := game.ImportWords(h.Words, records, editor)
+	if errors.Is(err, game.ErrInvalidWord) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportWords télécharge les mots du catalogue dans un format réimportable
+func (h *Handler) ExportWords(c *gin.Context) {
+	var req WordExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Format == "" {
+		req.Format = game.WordFormatJSON
+	}
+
+	records, err := game.ExportWords(h.Words, req.filter())
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	// Encoder avant de répondre pour qu'une erreur ne laisse pas un fichier tronqué
+	var body bytes.Buffer
+	if err := game.WriteWordRecords(&body, req.Format, records); err != nil {
+		internalError(c, err)
+		return
+	}
+
+	contentTypes := map[string]string{
+		game.WordFormatJSON: "application/json",
+		game.WordFormatYAML: "application/yaml",
+		game.WordFormatCSV:  "text/csv",
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="words.%s"`, req.Format))
+	c.Data(http.StatusOK, contentTypes[req.Format]+"; charset=utf-8", body.Bytes())
+}
+
+// WordDifficultyReport compare la difficulté des mots à celle calculée d'après
+// leurs lettres et les parties terminées, et signale les mots mal classés
+func (h *Handler) WordDifficultyReport(c *gin.Context) {
+	var req WordReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := game.DifficultyReport(h.Words, h.Games, req.filter(), req.All)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListWordAudit liste les dernières modifications de mots, les plus récentes en premier
+func (h *Handler) ListWordAudit(c *gin.Context) {
+	var req WordAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	entries, err := game.ListWordAudit(h.Words, req.WordID, req.Limit)
+	if err != nil {
+		internalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// wordEditor identifie l'administrateur qui modifie le catalogue
+func (h *Handler) wordEditor(c *gin.Context) (game.WordEditor, bool) {
+	user, ok := h.loadUser(c, currentUserID(c))
+	if !ok {
+		return game.WordEditor{}, false
+	}
+	return game.WordEditor{UserID: user.ID, UserName: user.Name}, true
+}
+
+// checkWordError répond à une erreur de modification de mot et indique si
+// l'opération a réussi
+func (h *Handler) checkWordError(c *gin.Context, err error) bool {
+	if errors.Is(err, game.ErrWordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
+		return false
+	}
+	if errors.Is(err, game.ErrWordExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Word already exists in this language"})
+		return false
+	}
+	if errors.Is(err, game.ErrInvalidWord) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if err != nil {
+		internalError(c, err)
+		return false
+	}
+	return true
+}
+
+// wordFormat déduit le format d'un fichier de mots de son Content-Type
+func wordFormat(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "yaml"):
+		return game.WordFormatYAML
+	case strings.Contains(contentType, "csv"):
+		return game.WordFormatCSV
+	default:
+		return game.WordFormatJSON
+	}
+}
@@ -1,37 +1,322 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
 	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // fuseaux horaires disponibles même sans base système
 
+	"github.com/N95Ryan/8bit-hangman-back/auth"
+	"github.com/N95Ryan/8bit-hangman-back/game"
 	"github.com/N95Ryan/8bit-hangman-back/handlers"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/N95Ryan/8bit-hangman-back/storage/memory"
+	"github.com/N95Ryan/8bit-hangman-back/storage/postgres"
+	"github.com/N95Ryan/8bit-hangman-back/storage/sqlite"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Chemin de la base SQLite ou DSN PostgreSQL (aucun des deux = stockage en mémoire)
+	dbPath := flag.String("db", os.Getenv("HANGMAN_DB"), "path to the SQLite database file (env HANGMAN_DB)")
+	databaseURL := flag.String("database-url", os.Getenv("DATABASE_URL"), "PostgreSQL DSN (env DATABASE_URL)")
+
+	// Durées de validité des tokens
+	flag.DurationVar(&models.AccessTokenTTL, "access-token-ttl", models.AccessTokenTTL, "lifetime of access tokens")
+	flag.DurationVar(&models.RefreshTokenTTL, "refresh-token-ttl", models.RefreshTokenTTL, "lifetime of refresh tokens")
+	sweepInterval := flag.Duration("session-sweep-interval", 10*time.Minute, "interval between purges of expired sessions")
+
+	// Tokens d'accès JWT (fichier de clés vide = tokens opaques)
+	jwtKeys := flag.String("jwt-keys", os.Getenv("HANGMAN_JWT_KEYS"), "path to the JWT key set file (env HANGMAN_JWT_KEYS)")
+	jwtIssuer := flag.String("jwt-issuer", "8bit-hangman", "issuer claim of JWT access tokens")
+
+	// Fuseau horaire des classements journaliers, hebdomadaires et mensuels
+	timezone := flag.String("leaderboard-timezone", os.Getenv("HANGMAN_TIMEZONE"), "IANA time zone delimiting leaderboard periods, UTC if empty (env HANGMAN_TIMEZONE)")
+
+	// Tentatives perdues pour un mot entier erroné
+	flag.IntVar(&game.WordGuessPenalty, "word-guess-penalty", game.WordGuessPenalty, "attempts lost for a wrong whole-word guess")
+
+	// Comparaison des lettres accentuées par défaut
+	matching := flag.String("letter-matching", game.DefaultMatching, "default letter matching of new games: fold (E reveals É) or strict")
+
+	// Langue des parties quand ni la requête ni Accept-Language n'en précisent une
+	flag.StringVar(&game.DefaultLanguage, "default-language", game.DefaultLanguage, "language of new games when the request names none: "+strings.Join(game.Languages(), ", "))
+
+	// Répertoire de fichiers de mots importés au démarrage puis à chaque modification
+	wordsDir := flag.String("words-dir", os.Getenv("HANGMAN_WORDS_DIR"), "directory of JSON, YAML or CSV word files imported at startup and on change (env HANGMAN_WORDS_DIR)")
+
+	// Relecture des mots modifiés par les autres serveurs partageant la base
+	wordSyncInterval := flag.Duration("word-sync-interval", 10*time.Second, "interval between checks for word changes made by other servers")
+
+	// Nombre de jours avant qu'un mot du défi quotidien puisse revenir
+	flag.IntVar(&game.DailyWindow, "daily-window", game.DailyWindow, "number of days before a daily challenge word can be picked again")
+
+	// Défi quotidien et clé secrète du tirage de son mot
+	daily := flag.Bool("daily", true, "enable the daily challenge")
+	flag.StringVar(&game.DailySecret, "daily-secret", os.Getenv("HANGMAN_DAILY_SECRET"), "secret mixed into the daily word selection so it can't be predicted, required with -daily (env HANGMAN_DAILY_SECRET)")
+
+	// Nombre d'entrées du haut du classement suivies par le flux SSE
+	flag.IntVar(&game.LeaderboardStreamTop, "leaderboard-stream-top", game.LeaderboardStreamTop, "number of top leaderboard entries per difficulty followed by the live stream")
+
+	// Compte à rebours des courses multijoueurs
+	flag.DurationVar(&game.MatchStartDelay, "match-start-delay", game.MatchStartDelay, "countdown between the start of a multiplayer match and its first guesses")
+
+	// Saisons créées automatiquement et fréquence de vérification des fins de saison
+	seasonLength := flag.String("season-length", game.PeriodMonthly, "length of automatically created seasons: daily, weekly, monthly or empty to disable")
+	seasonInterval := flag.Duration("season-rollover-interval", time.Minute, "interval between checks for ended seasons")
+
+	// Graine des parties fixable par tous les joueurs (tests de bout en bout)
+	allowSeeds := flag.Bool("allow-game-seeds", false, "let any player set the seed of a new game instead of administrators only (testing)")
+
+	// Utilisateurs promus administrateurs au démarrage
+	admins := flag.String("admins", os.Getenv("HANGMAN_ADMINS"), "comma-separated usernames granted the admin role (env HANGMAN_ADMINS)")
+
+	// Pages d'autres origines autorisées à appeler l'API et à ouvrir ses WebSockets
+	allowedOrigins := flag.String("allowed-origins", os.Getenv("HANGMAN_ALLOWED_ORIGINS"), "comma-separated origins of web pages allowed to call the API and open its WebSockets, besides its own (env HANGMAN_ALLOWED_ORIGINS)")
+	flag.Parse()
+
 	// Configuration du port
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	location, err := time.LoadLocation(*timezone)
+	if err != nil {
+		log.Fatalf("invalid leaderboard time zone: %v", err)
+	}
+	game.LeaderboardLocation = location
+
+	if !game.ValidMatching(*matching) {
+		log.Fatalf("invalid letter matching %q: must be fold or strict", *matching)
+	}
+	game.DefaultMatching = *matching
+
+	if *daily && game.DailySecret == "" {
+		log.Fatal("the daily challenge needs a secret: set -daily-secret (env HANGMAN_DAILY_SECRET), or disable it with -daily=false")
+	}
+	if game.DailyWindow < 0 {
+		log.Fatalf("invalid daily window %d: must not be negative", game.DailyWindow)
+	}
+	if *wordSyncInterval <= 0 {
+		log.Fatalf("invalid word sync interval %s: must be positive", *wordSyncInterval)
+	}
+	if game.MatchStartDelay < 0 {
+		log.Fatalf("invalid match start delay %s: must not be negative", game.MatchStartDelay)
+	}
+	if game.LeaderboardStreamTop < 1 || game.LeaderboardStreamTop > game.MaxLeaderboardLimit {
+		log.Fatalf("invalid leaderboard stream top %d: must be between 1 and %d", game.LeaderboardStreamTop, game.MaxLeaderboardLimit)
+	}
+
+	if err := game.ValidateSeasonLength(*seasonLength); err != nil {
+		log.Fatal(err)
+	}
+
+	origins, err := parseOrigins(*allowedOrigins)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialisation du stockage et des handlers
+	store, err := openStore(*dbPath, *databaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	// Mots des nouvelles parties : fichiers importés dans le stockage, qui reçoit
+	// les mots intégrés s'il est vide
+	if *wordsDir != "" {
+		count, err := game.ImportWordDir(store, *wordsDir)
+		if err != nil {
+			log.Fatalf("importing word files: %v", err)
+		}
+		log.Printf("imported %d new, changed or disabled words from %s", count, *wordsDir)
+
+		stopWatching, err := game.WatchWordDir(*wordsDir, func(dir string) (int, error) {
+			return game.ImportWordDir(store, dir)
+		})
+		if err != nil {
+			log.Fatalf("watching word files: %v", err)
+		}
+		defer stopWatching()
+	}
+	if err := game.InitWordStore(store); err != nil {
+		log.Fatalf("loading words: %v", err)
+	}
+	stopWordSync := game.StartWordCatalogSync(store, *wordSyncInterval)
+	defer stopWordSync()
+
+	if !game.SupportedLanguage(game.DefaultLanguage) {
+		log.Fatalf("invalid default language %q: must be one of %s", game.DefaultLanguage, strings.Join(game.Languages(), ", "))
+	}
+
+	h := handlers.New(store, store, store, store, store, store, store)
+
+	if *jwtKeys != "" {
+		keys, err := auth.LoadKeySet(*jwtKeys)
+		if err != nil {
+			log.Fatalf("loading JWT keys: %v", err)
+		}
+		h.JWT = auth.NewJWTManager(keys, *jwtIssuer)
+	}
+
+	grantAdmins(store, *admins)
+	h.AllowSeeds = *allowSeeds
+	h.AllowedOrigins = origins
+
+	// Purge périodique des sessions expirées
+	stopSweeper := models.StartSessionSweeper(store, *sweepInterval)
+	defer stopSweeper()
+
+	// Archivage des saisons terminées et création de la saison suivante
+	stopRollover := game.StartSeasonRollover(store, *seasonInterval, *seasonLength)
+	defer stopRollover()
+
 	// Initialisation du routeur Gin
 	r := gin.Default()
+	r.Use(h.CORS)
 
 	// Routes pour les jeux
-	r.POST("/api/games", handlers.CreateGame)
-	r.GET("/api/games/:id", handlers.GetGame)
-	r.POST("/api/games/:id/guess", handlers.SubmitGuess)
-	r.GET("/api/games/:id/hint", handlers.GetHint)
-	r.DELETE("/api/games/:id", handlers.AbandonGame)
+	r.POST("/api/games", h.OptionalAuth, h.CreateGame)
+	r.GET("/api/games/:id", h.OptionalAuth, h.GetGame)
+	r.POST("/api/games/:id/guess", h.OptionalAuth, h.SubmitGuess)
+	r.GET("/api/games/:id/hint", h.OptionalAuth, h.GetHint)
+	r.DELETE("/api/games/:id", h.OptionalAuth, h.AbandonGame)
+	r.GET("/api/games/:id/ws", h.OptionalAuth, h.GameSocket)
+	r.GET("/api/share/:code", h.GetSharedGame)
+	r.GET("/api/categories", h.ListCategories)
 
 	// Routes pour les utilisateurs
-	r.POST("/api/users/register", handlers.RegisterUser)
-	r.POST("/api/users/login", handlers.LoginUser)
+	r.POST("/api/users/register", h.RegisterUser)
+	r.POST("/api/users/login", h.LoginUser)
+	r.POST("/api/users/refresh", h.RefreshToken)
 
 	// Routes pour les scores
-	r.GET("/api/leaderboard", handlers.GetLeaderboard)
-	r.POST("/api/leaderboard", handlers.SubmitScore)
+	r.GET("/api/leaderboard", h.GetLeaderboard)
+	r.GET("/api/leaderboard/players/:id", h.GetPlayerStanding)
+	r.GET("/api/leaderboard/stream", h.StreamLeaderboard)
+	if *daily {
+		r.GET("/api/daily/leaderboard", h.GetDailyLeaderboard)
+	}
+	r.GET("/api/seasons", h.ListSeasons)
+	r.GET("/api/seasons/:id/leaderboard", h.GetSeasonLeaderboard)
+
+	// Routes nécessitant un token (Authorization: Bearer <token>)
+	authorized := r.Group("/api", h.RequireAuth)
+	authorized.POST("/leaderboard", h.SubmitScore)
+	if *daily {
+		authorized.GET("/daily", h.GetDaily)
+	}
+	authorized.POST("/matches", h.CreateMatch)
+	authorized.POST("/matches/join", h.JoinMatch)
+	authorized.GET("/matches/:id", h.GetMatch)
+	authorized.POST("/matches/:id/start", h.StartMatch)
+	authorized.POST("/matches/:id/leave", h.LeaveMatch)
+	authorized.POST("/matches/:id/guess", h.SubmitMatchGuess)
+	authorized.GET("/users/me", h.GetUserProfile)
+	authorized.PUT("/users/me", h.UpdateUserProfile)
+	authorized.POST("/users/logout", h.LogoutUser)
+	authorized.GET("/users/me/sessions", h.ListSessions)
+	authorized.DELETE("/users/me/sessions/:id", h.RevokeSession)
+
+	// Routes réservées aux administrateurs
+	admin := authorized.Group("", h.RequireRole(models.RoleAdmin))
+	admin.POST("/seasons", h.CreateSeason)
+	admin.GET("/admin/words", h.ListWords)
+	admin.POST("/admin/words", h.CreateWord)
+	admin.GET("/admin/words/export", h.ExportWords)
+	admin.POST("/admin/words/import", h.ImportWords)
+	admin.GET("/admin/words/audit", h.ListWordAudit)
+	admin.GET("/admin/words/report", h.WordDifficultyReport)
+	admin.GET("/admin/words/:id", h.GetWord)
+	admin.PUT("/admin/words/:id", h.UpdateWord)
+	admin.DELETE("/admin/words/:id", h.DeleteWord)
+	admin.POST("/admin/words/:id/enable", h.EnableWord)
+	admin.POST("/admin/words/:id/disable", h.DisableWord)
 
 	// Démarrage du serveur
-	r.Run(":" + port)
+	if err := r.Run(":" + port); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// store regroupe les interfaces de stockage implémentées par chaque backend
+type store interface {
+	game.GameStore
+	game.LeaderboardStore
+	game.SeasonStore
+	game.WordStore
+	game.DailyStore
+	game.MatchStore
+	models.UserStore
+	Close() error
+}
+
+// openStore choisit le backend de stockage selon la configuration
+func openStore(dbPath, databaseURL string) (store, error) {
+	switch {
+	case dbPath != "" && databaseURL != "":
+		return nil, errors.New("-db and -database-url are mutually exclusive")
+	case databaseURL != "":
+		s, err := postgres.Open(databaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("opening PostgreSQL database: %w", err)
+		}
+		return s, nil
+	case dbPath != "":
+		s, err := sqlite.Open(dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("opening SQLite database %s: %w", dbPath, err)
+		}
+		return s, nil
+	default:
+		return memory.New(), nil
+	}
+}
+
+// parseOrigins lit une liste d'origines séparées par des virgules, chacune
+// formée d'un schéma http ou https et d'un hôte ("https://example.com:8443")
+func parseOrigins(list string) ([]string, error) {
+	var origins []string
+	for _, origin := range strings.Split(list, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return nil, fmt.Errorf("invalid allowed origin %q: must be a scheme and a host, like https://example.com", origin)
+		}
+		origins = append(origins, u.Scheme+"://"+strings.ToLower(u.Host))
+	}
+	return origins, nil
+}
+
+// grantAdmins attribue le rôle admin aux utilisateurs listés qui existent déjà.
+// Un nom encore libre n'est jamais promu à l'inscription : n'importe qui
+// pourrait le réserver avant son propriétaire.
+func grantAdmins(users models.UserStore, usernames string) {
+	for _, username := range strings.Split(usernames, ",") {
+		username = strings.TrimSpace(username)
+		if username == "" {
+			continue
+		}
+
+		err := models.GrantRole(users, username, models.RoleAdmin)
+		if errors.Is(err, models.ErrUserNotFound) {
+			log.Printf("admin %q is not registered: register it, then restart to grant the role", username)
+			continue
+		}
+		if err != nil {
+			log.Fatalf("granting admin role to %q: %v", username, err)
+		}
+	}
 }
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Durées de validité des tokens, modifiables au démarrage
+var (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Session représente une connexion d'un utilisateur. Seules les empreintes
+// des tokens sont stockées : une fuite de la base ne permet pas de les réutiliser.
+type Session struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"-"`
+	AccessTokenHash  string    `json:"-"`
+	RefreshTokenHash string    `json:"-"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	RefreshedAt      time.Time `json:"refreshed_at"`
+	UserAgent        string    `json:"user_agent"`
+}
+
+// TokenPair contient les tokens remis au client, en clair
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	AccessExpiresAt  time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// CreateSession ouvre une nouvelle session pour un utilisateur
+func CreateSession(store UserStore, userID, userAgent string) (*Session, TokenPair, error) {
+	now := time.Now()
+	session := &Session{
+		UserID:      userID,
+		CreatedAt:   now,
+		RefreshedAt: now,
+		UserAgent:   userAgent,
+	}
+	var tokens TokenPair
+	err := utils.RetryOnDuplicateID(func() error {
+		session.ID = utils.NewID()
+		tokens = issueTokens(session, now)
+		return store.CreateSession(session)
+	})
+	if err != nil {
+		return nil, TokenPair{}, err
+	}
+
+	return session, tokens, nil
+}
+
+// RefreshSession échange un token de rafraîchissement contre une nouvelle paire
+// de tokens. L'ancien token de rafraîchissement ne peut plus être utilisé.
+func RefreshSession(store UserStore, refreshToken string) (*Session, TokenPair, error) {
+	session, err := store.GetSessionByRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		return nil, TokenPair{}, err
+	}
+
+	now := time.Now()
+	if !now.Before(session.RefreshExpiresAt) {
+		return nil, TokenPair{}, ErrTokenExpired
+	}
+
+	oldRefreshHash := session.RefreshTokenHash
+	session.RefreshedAt = now
+	tokens := issueTokens(session, now)
+
+	if err := store.RotateSession(session, oldRefreshHash); err != nil {
+		return nil, TokenPair{}, err
+	}
+
+	return session, tokens, nil
+}
+
+// ValidateToken vérifie un token d'accès et retourne la session associée
+func ValidateToken(store UserStore, accessToken string) (*Session, error) {
+	session, err := store.GetSessionByAccessToken(hashToken(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if !time.Now().Before(session.AccessExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	return session, nil
+}
+
+// ListSessions retourne les sessions encore valides d'un utilisateur
+func ListSessions(store UserStore, userID string) ([]Session, error) {
+	sessions, err := store.ListUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := []Session{}
+	for _, session := range sessions {
+		if now.Before(session.RefreshExpiresAt) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// RevokeSession supprime une session appartenant à l'utilisateur donné
+func RevokeSession(store UserStore, userID, sessionID string) error {
+	sessions, err := store.ListUserSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	// Une session d'un autre utilisateur est traitée comme inexistante
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			return store.DeleteSession(sessionID)
+		}
+	}
+	return ErrSessionNotFound
+}
+
+// PurgeExpiredSessions supprime les sessions qui ne peuvent plus être rafraîchies
+func PurgeExpiredSessions(store UserStore) (int, error) {
+	return store.DeleteExpiredSessions(time.Now())
+}
+
+// StartSessionSweeper purge périodiquement les sessions expirées.
+// La fonction retournée arrête le balayage.
+func StartSessionSweeper(store UserStore, interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				n, err := PurgeExpiredSessions(store)
+				if err != nil {
+					log.Printf("purging expired sessions: %v", err)
+				} else if n > 0 {
+					log.Printf("purged %d expired sessions", n)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// issueTokens génère une nouvelle paire de tokens et met à jour la session
+func issueTokens(session *Session, now time.Time) TokenPair {
+	tokens := TokenPair{
+		AccessToken:      utils.GenerateToken(),
+		RefreshToken:     utils.GenerateToken(),
+		AccessExpiresAt:  now.Add(AccessTokenTTL),
+		RefreshExpiresAt: now.Add(RefreshTokenTTL),
+	}
+
+	session.AccessTokenHash = hashToken(tokens.AccessToken)
+	session.RefreshTokenHash = hashToken(tokens.RefreshToken)
+	session.AccessExpiresAt = tokens.AccessExpiresAt
+	session.RefreshExpiresAt = tokens.RefreshExpiresAt
+	return tokens
+}
+
+// hashToken calcule l'empreinte SHA-256 d'un token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
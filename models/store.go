@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Erreurs renvoyées par les implémentations de stockage
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUsernameTaken = errors.New("username already exists")
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrTokenExpired  = errors.New("token expired")
+
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrInvalidCredentials couvre à la fois un nom inconnu et un mauvais mot de passe
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// UserStore décrit le stockage des utilisateurs et de leurs sessions
+type UserStore interface {
+	// CreateUser enregistre un nouvel utilisateur (ErrUsernameTaken si le nom est
+	// pris, utils.ErrDuplicateID si l'ID existe déjà)
+	CreateUser(user *User) error
+	// GetUser récupère un utilisateur par son ID (ErrUserNotFound si absent)
+	GetUser(id string) (*User, error)
+	// GetUserByName récupère un utilisateur par son nom (ErrUserNotFound si absent)
+	GetUserByName(username string) (*User, error)
+	// UpdateUser applique fn à l'utilisateur puis enregistre le résultat de façon atomique
+	UpdateUser(id string, fn func(*User) error) (*User, error)
+
+	// CreateSession enregistre une nouvelle session (utils.ErrDuplicateID si l'ID ou une empreinte existe déjà)
+	CreateSession(session *Session) error
+	// GetSessionByAccessToken récupère une session par l'empreinte de son token d'accès (ErrInvalidToken si inconnue)
+	GetSessionByAccessToken(tokenHash string) (*Session, error)
+	// GetSessionByRefreshToken récupère une session par l'empreinte de son token de rafraîchissement (ErrInvalidToken si inconnue)
+	GetSessionByRefreshToken(tokenHash string) (*Session, error)
+	// RotateSession remplace les tokens d'une session si son token de rafraîchissement
+	// vaut toujours oldRefreshHash (ErrInvalidToken sinon, par exemple s'il a déjà servi)
+	RotateSession(session *Session, oldRefreshHash string) error
+	// ListUserSessions retourne les sessions d'un utilisateur, les plus récentes en premier
+	ListUserSessions(userID string) ([]Session, error)
+	// DeleteSession supprime une session (ErrSessionNotFound si absente)
+	DeleteSession(id string) error
+	// DeleteUserSessions supprime toutes les sessions d'un utilisateur
+	DeleteUserSessions(userID string) error
+	// DeleteExpiredSessions supprime les sessions dont le token de rafraîchissement a expiré avant now
+	DeleteExpiredSessions(now time.Time) (int, error)
+}
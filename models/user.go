@@ -2,7 +2,6 @@ package models
 
 import (
 	"errors"
-	"sync"
 	"time"
 
 	"github.com/N95Ryan/8bit-hangman-back/utils"
@@ -18,33 +17,38 @@ type User struct {
 	GamesPlayed int       `json:"games_played"`
 	GamesWon    int       `json:"games_won"`
 	HighScore   int       `json:"high_score"`
+	Roles       []string  `json:"roles"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// Stockage en mémoire des utilisateurs
-var (
-	users       = make(map[string]*User)
-	usersByName = make(map[string]string) // map[username]userID
-	usersMutex  sync.RWMutex
+// Rôles attribuables aux utilisateurs
+const (
+	RoleAdmin = "admin"
 )
 
-// Stockage des tokens d'authentification
-var (
-	authTokens  = make(map[string]string) // map[token]userID
-	tokensMutex sync.RWMutex
-)
+// HasRole indique si l'utilisateur possède le rôle donné
+func (u *User) HasRole(role string) bool {
+	return utils.Contains(u.Roles, role)
+}
 
-// CreateUser crée un nouvel utilisateur
-func CreateUser(username, password, email string) (*User, error) {
-	usersMutex.Lock()
-	defer usersMutex.Unlock()
+// RecordGame compte une partie soumise au classement dans les statistiques de
+// l'utilisateur. Les stockages l'appliquent dans la transaction qui enregistre le score.
+func (u *User) RecordGame(won bool, score int, at time.Time) {
+	u.GamesPlayed++
+	if won {
+		u.GamesWon++
+	}
 
-	// Vérifier si le nom d'utilisateur existe déjà
-	if _, exists := usersByName[username]; exists {
-		return nil, errors.New("username already exists")
+	if score > u.HighScore {
+		u.HighScore = score
 	}
 
+	u.UpdatedAt = at
+}
+
+// CreateUser crée un nouvel utilisateur
+func CreateUser(store UserStore, username, password, email string) (*User, error) {
 	// Hasher le mot de passe
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -53,132 +57,105 @@ func CreateUser(username, password, email string) (*User, error) {
 
 	// Créer l'utilisateur
 	user := &User{
-		ID:        utils.GenerateID(),
 		Name:      username,
 		Email:     email,
 		Password:  string(hashedPassword),
+		Roles:     []string{},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	// Stocker l'utilisateur
-	users[user.ID] = user
-	usersByName[username] = user.ID
+	// Stocker l'utilisateur (le stockage refuse les noms déjà pris), avec un
+	// nouvel identifiant en cas de collision
+	err = utils.RetryOnDuplicateID(func() error {
+		user.ID = utils.NewID()
+		return store.CreateUser(user)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return user, nil
 }
 
 // GetUser récupère un utilisateur par son ID
-func GetUser(id string) (*User, bool) {
-	usersMutex.RLock()
-	defer usersMutex.RUnlock()
-
-	user, exists := users[id]
-	return user, exists
+func GetUser(store UserStore, id string) (*User, error) {
+	return store.GetUser(id)
 }
 
 // UserExists vérifie si un utilisateur existe par son nom
-func UserExists(username string) bool {
-	usersMutex.RLock()
-	defer usersMutex.RUnlock()
-
-	_, exists := usersByName[username]
-	return exists
+func UserExists(store UserStore, username string) (bool, error) {
+	_, err := store.GetUserByName(username)
+	if errors.Is(err, ErrUserNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// AuthenticateUser authentifie un utilisateur et génère un token
-func AuthenticateUser(username, password string) (*User, string, error) {
-	usersMutex.RLock()
-	userID, exists := usersByName[username]
-	usersMutex.RUnlock()
-
-	if !exists {
-		return nil, "", errors.New("user not found")
+// AuthenticateUser vérifie le nom d'utilisateur et le mot de passe
+func AuthenticateUser(store UserStore, username, password string) (*User, error) {
+	user, err := store.GetUserByName(username)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
 	}
-
-	user := users[userID]
 
 	// Vérifier le mot de passe
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, "", errors.New("invalid password")
+		return nil, ErrInvalidCredentials
 	}
 
-	// Générer un token
-	token := utils.GenerateID()
-
-	// Stocker le token
-	tokensMutex.Lock()
-	authTokens[token] = user.ID
-	tokensMutex.Unlock()
-
-	return user, token, nil
+	return user, nil
 }
 
-// UpdateUser met à jour les informations d'un utilisateur
-func UpdateUser(user *User) error {
-	usersMutex.Lock()
-	defer usersMutex.Unlock()
-
-	if _, exists := users[user.ID]; !exists {
-		return errors.New("user not found")
+// GrantRole attribue un rôle à un utilisateur désigné par son nom
+func GrantRole(store UserStore, username, role string) error {
+	user, err := store.GetUserByName(username)
+	if err != nil {
+		return err
 	}
 
-	user.UpdatedAt = time.Now()
-	users[user.ID] = user
-
-	return nil
+	_, err = store.UpdateUser(user.ID, func(user *User) error {
+		if !user.HasRole(role) {
+			user.Roles = append(user.Roles, role)
+			user.UpdatedAt = time.Now()
+		}
+		return nil
+	})
+	return err
 }
 
-// UpdateUserPassword met à jour le mot de passe d'un utilisateur
-func UpdateUserPassword(userID, newPassword string) error {
-	usersMutex.Lock()
-	defer usersMutex.Unlock()
-
-	user, exists := users[userID]
-	if !exists {
-		return errors.New("user not found")
-	}
+// UpdateUserEmail met à jour l'adresse email d'un utilisateur
+func UpdateUserEmail(store UserStore, userID, email string) (*User, error) {
+	return store.UpdateUser(userID, func(user *User) error {
+		user.Email = email
+		user.UpdatedAt = time.Now()
+		return nil
+	})
+}
 
+// UpdateUserPassword met à jour le mot de passe d'un utilisateur et révoque
+// toutes ses sessions
+func UpdateUserPassword(store UserStore, userID, newPassword string) error {
 	// Hasher le nouveau mot de passe
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	user.Password = string(hashedPassword)
-	user.UpdatedAt = time.Now()
-
-	return nil
-}
-
-// UpdateUserStats met à jour les statistiques d'un utilisateur
-func UpdateUserStats(userID string, won bool, score int) error {
-	usersMutex.Lock()
-	defer usersMutex.Unlock()
-
-	user, exists := users[userID]
-	if !exists {
-		return errors.New("user not found")
-	}
-
-	user.GamesPlayed++
-	if won {
-		user.GamesWon++
-	}
-
-	if score > user.HighScore {
-		user.HighScore = score
+	_, err = store.UpdateUser(userID, func(user *User) error {
+		user.Password = string(hashedPassword)
+		user.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	user.UpdatedAt = time.Now()
-	return nil
-}
-
-// ValidateToken vérifie si un token est valide et retourne l'ID utilisateur associé
-func ValidateToken(token string) (string, bool) {
-	tokensMutex.RLock()
-	defer tokensMutex.RUnlock()
-
-	userID, exists := authTokens[token]
-	return userID, exists
+	return store.DeleteUserSessions(userID)
 }
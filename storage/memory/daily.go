@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+)
+
+// CreateDailyChallenge enregistre le défi d'un jour
+func (s *Store) CreateDailyChallenge(challenge *game.DailyChallenge) error {
+	s.dailyMutex.Lock()
+	defer s.dailyMutex.Unlock()
+
+	key := challenge.Language + "/" + challenge.Day
+	if _, exists := s.dailyChallenges[key]; exists {
+		return game.ErrDailyExists
+	}
+
+	c := *challenge
+	s.dailyChallenges[key] = &c
+	return nil
+}
+
+// GetDailyChallenge récupère le défi d'un jour
+func (s *Store) GetDailyChallenge(day, language string) (*game.DailyChallenge, error) {
+	s.dailyMutex.RLock()
+	defer s.dailyMutex.RUnlock()
+
+	challenge, exists := s.dailyChallenges[language+"/"+day]
+	if !exists {
+		return nil, game.ErrDailyNotFound
+	}
+
+	c := *challenge
+	return &c, nil
+}
+
+// ListDailyWords retourne les mots des défis d'une langue entre deux jours, dans l'ordre des jours
+func (s *Store) ListDailyWords(language, from, to string) ([]string, error) {
+	s.dailyMutex.RLock()
+	defer s.dailyMutex.RUnlock()
+
+	var challenges []*game.DailyChallenge
+	for _, challenge := range s.dailyChallenges {
+		if challenge.Language == language && challenge.Day >= from && challenge.Day < to {
+			challenges = append(challenges, challenge)
+		}
+	}
+	sort.Slice(challenges, func(i, j int) bool {
+		return challenges[i].Day < challenges[j].Day
+	})
+
+	words := make([]string, len(challenges))
+	for i, challenge := range challenges {
+		words[i] = challenge.Word
+	}
+	return words, nil
+}
+
+// AddDailyPlay enregistre la partie d'un joueur pour un défi
+func (s *Store) AddDailyPlay(play game.DailyPlay) error {
+	s.dailyMutex.Lock()
+	defer s.dailyMutex.Unlock()
+
+	key := play.Language + "/" + play.Day + "/" + play.PlayerID
+	if _, exists := s.dailyPlays[key]; exists {
+		return game.ErrDailyAlreadyPlayed
+	}
+
+	s.dailyPlays[key] = play
+	return nil
+}
+
+// GetDailyPlay récupère la partie d'un joueur pour un défi
+func (s *Store) GetDailyPlay(day, language, playerID string) (*game.DailyPlay, error) {
+	s.dailyMutex.RLock()
+	defer s.dailyMutex.RUnlock()
+
+	play, exists := s.dailyPlays[language+"/"+day+"/"+playerID]
+	if !exists {
+		return nil, game.ErrDailyNotFound
+	}
+	return &play, nil
+}
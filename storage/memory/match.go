@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// CreateMatch enregistre une nouvelle course
+func (s *Store) CreateMatch(m *game.Match) error {
+	s.matchesMutex.Lock()
+	defer s.matchesMutex.Unlock()
+
+	if _, exists := s.matches[m.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.matchesByCode[m.Code]; exists {
+		return utils.ErrDuplicateID
+	}
+
+	s.matches[m.ID] = cloneMatch(m)
+	s.matchesByCode[m.Code] = m.ID
+	return nil
+}
+
+// GetMatch récupère une course par son ID
+func (s *Store) GetMatch(id string) (*game.Match, error) {
+	s.matchesMutex.RLock()
+	defer s.matchesMutex.RUnlock()
+
+	m, exists := s.matches[id]
+	if !exists {
+		return nil, game.ErrMatchNotFound
+	}
+	return cloneMatch(m), nil
+}
+
+// GetMatchByCode récupère une course par son code
+func (s *Store) GetMatchByCode(code string) (*game.Match, error) {
+	s.matchesMutex.RLock()
+	defer s.matchesMutex.RUnlock()
+
+	id, exists := s.matchesByCode[code]
+	if !exists {
+		return nil, game.ErrMatchNotFound
+	}
+	return cloneMatch(s.matches[id]), nil
+}
+
+// SaveMatch met à jour une course existante (son code ne change pas), si elle
+// n'a pas été enregistrée depuis sa lecture
+func (s *Store) SaveMatch(m *game.Match) error {
+	s.matchesMutex.Lock()
+	defer s.matchesMutex.Unlock()
+
+	current, exists := s.matches[m.ID]
+	if !exists {
+		return game.ErrMatchNotFound
+	}
+	if current.Version != m.Version {
+		return game.ErrMatchConflict
+	}
+
+	m.Version++
+	updated := cloneMatch(m)
+	updated.Code = current.Code
+	s.matches[m.ID] = updated
+	return nil
+}
+
+// cloneMatch copie une course pour que l'appelant ne partage pas la liste des
+// joueurs avec le stockage
+func cloneMatch(m *game.Match) *game.Match {
+	c := *m
+	c.Players = slices.Clone(m.Players)
+	return &c
+}
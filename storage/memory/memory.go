@@ -0,0 +1,685 @@
+// Package memory fournit une implémentation en mémoire du stockage.
+// Toutes les données sont perdues au redémarrage du serveur.
+package memory
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Store implémente game.GameStore, game.LeaderboardStore, game.SeasonStore,
+// game.WordStore, game.DailyStore, game.MatchStore et models.UserStore
+type Store struct {
+	gamesMutex       sync.RWMutex
+	games            map[string]*game.Game
+	gamesByShareCode map[string]string // map[code de partage]gameID
+
+	// Les scores sont gardés triés à l'insertion pour que les lectures n'aient
+	// qu'à découper une page. leaderboardByTime les range par date de soumission
+	// pour retrouver ceux d'une période sans parcourir tout l'historique.
+	leaderboardMutex        sync.RWMutex
+	leaderboard             []game.LeaderboardEntry
+	leaderboardByTime       []game.LeaderboardEntry
+	leaderboardByDifficulty map[string][]game.LeaderboardEntry
+	leaderboardIDs          map[string]struct{}
+	leaderboardGames        map[string]struct{} // parties dont le score a été soumis
+
+	seasonsMutex  sync.RWMutex
+	seasons       map[string]*game.Season
+	seasonNames   map[string]string                  // map[nom]seasonID
+	seasonEntries map[string][]game.LeaderboardEntry // classements archivés, triés
+
+	wordsMutex   sync.RWMutex
+	words        map[string]*game.WordEntry
+	wordKeys     map[string]string // map[langue + clé du mot]wordID
+	wordAudit    []game.WordAudit  // dans l'ordre d'enregistrement
+	wordAuditIDs map[string]struct{}
+
+	dailyMutex      sync.RWMutex
+	dailyChallenges map[string]*game.DailyChallenge // map[langue + jour]défi
+	dailyPlays      map[string]game.DailyPlay       // map[langue + jour + joueur]partie
+
+	matchesMutex  sync.RWMutex
+	matches       map[string]*game.Match
+	matchesByCode map[string]string // map[code]matchID
+
+	usersMutex  sync.RWMutex
+	users       map[string]*models.User
+	usersByName map[string]string // map[username]userID
+
+	sessionsMutex     sync.RWMutex
+	sessions          map[string]*models.Session
+	sessionsByAccess  map[string]string // map[empreinte du token d'accès]sessionID
+	sessionsByRefresh map[string]string // map[empreinte du token de rafraîchissement]sessionID
+}
+
+// New crée un stockage en mémoire vide
+func New() *Store {
+	return &Store{
+		games:                   make(map[string]*game.Game),
+		gamesByShareCode:        make(map[string]string),
+		leaderboardByDifficulty: make(map[string][]game.LeaderboardEntry),
+		leaderboardIDs:          make(map[string]struct{}),
+		leaderboardGames:        make(map[string]struct{}),
+		seasons:                 make(map[string]*game.Season),
+		seasonNames:             make(map[string]string),
+		seasonEntries:           make(map[string][]game.LeaderboardEntry),
+		words:                   make(map[string]*game.WordEntry),
+		wordKeys:                make(map[string]string),
+		wordAuditIDs:            make(map[string]struct{}),
+		dailyChallenges:         make(map[string]*game.DailyChallenge),
+		dailyPlays:              make(map[string]game.DailyPlay),
+		matches:                 make(map[string]*game.Match),
+		matchesByCode:           make(map[string]string),
+		users:                   make(map[string]*models.User),
+		usersByName:             make(map[string]string),
+		sessions:                make(map[string]*models.Session),
+		sessionsByAccess:        make(map[string]string),
+		sessionsByRefresh:       make(map[string]string),
+	}
+}
+
+// Close ne fait rien : il n'y a aucune ressource à libérer
+func (s *Store) Close() error {
+	return nil
+}
+
+// CreateGame enregistre une nouvelle partie
+func (s *Store) CreateGame(g *game.Game) error {
+	s.gamesMutex.Lock()
+	defer s.gamesMutex.Unlock()
+
+	if _, exists := s.games[g.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.gamesByShareCode[g.ShareCode]; g.ShareCode != "" && exists {
+		return utils.ErrDuplicateID
+	}
+
+	s.games[g.ID] = cloneGame(g)
+	if g.ShareCode != "" {
+		s.gamesByShareCode[g.ShareCode] = g.ID
+	}
+	return nil
+}
+
+// SaveGame met à jour une partie existante (son code de partage ne change pas)
+func (s *Store) SaveGame(g *game.Game) error {
+	s.gamesMutex.Lock()
+	defer s.gamesMutex.Unlock()
+
+	current, exists := s.games[g.ID]
+	if !exists {
+		return game.ErrGameNotFound
+	}
+	if current.Version != g.Version {
+		return game.ErrGameConflict
+	}
+
+	g.Version++
+	updated := cloneGame(g)
+	updated.ShareCode = current.ShareCode
+	s.games[g.ID] = updated
+	return nil
+}
+
+// GetGame récupère une partie par son ID
+func (s *Store) GetGame(id string) (*game.Game, error) {
+	s.gamesMutex.RLock()
+	defer s.gamesMutex.RUnlock()
+
+	g, exists := s.games[id]
+	if !exists {
+		return nil, game.ErrGameNotFound
+	}
+	return cloneGame(g), nil
+}
+
+// GetGameByShareCode récupère une partie par son code de partage
+func (s *Store) GetGameByShareCode(code string) (*game.Game, error) {
+	s.gamesMutex.RLock()
+	defer s.gamesMutex.RUnlock()
+
+	id, exists := s.gamesByShareCode[code]
+	if !exists {
+		return nil, game.ErrGameNotFound
+	}
+	return cloneGame(s.games[id]), nil
+}
+
+// DeleteGame supprime une partie
+func (s *Store) DeleteGame(id string) error {
+	s.gamesMutex.Lock()
+	defer s.gamesMutex.Unlock()
+
+	g, exists := s.games[id]
+	if !exists {
+		return game.ErrGameNotFound
+	}
+
+	delete(s.gamesByShareCode, g.ShareCode)
+	delete(s.games, id)
+	return nil
+}
+
+// WordStats compte les parties terminées et gagnées de chaque mot
+func (s *Store) WordStats() ([]game.WordStats, error) {
+	s.gamesMutex.RLock()
+	defer s.gamesMutex.RUnlock()
+
+	byWord := map[[2]string]*game.WordStats{}
+	for _, g := range s.games {
+		if g.Status != "won" && g.Status != "lost" {
+			continue
+		}
+		key := [2]string{g.Language, g.Word}
+		st, exists := byWord[key]
+		if !exists {
+			st = &game.WordStats{Language: g.Language, Word: g.Word}
+			byWord[key] = st
+		}
+		st.Played++
+		if g.Status == "won" {
+			st.Won++
+		}
+	}
+
+	stats := make([]game.WordStats, 0, len(byWord))
+	for _, st := range byWord {
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Language != stats[j].Language {
+			return stats[i].Language < stats[j].Language
+		}
+		return stats[i].Word < stats[j].Word
+	})
+	return stats, nil
+}
+
+// AddLeaderboardEntry insère un score à sa place dans le classement
+func (s *Store) AddLeaderboardEntry(entry game.LeaderboardEntry) error {
+	s.leaderboardMutex.Lock()
+	defer s.leaderboardMutex.Unlock()
+
+	return s.insertLeaderboardEntry(entry)
+}
+
+// SubmitLeaderboardEntry insère un score et met à jour les statistiques du
+// joueur sous les verrous des utilisateurs et du classement
+func (s *Store) SubmitLeaderboardEntry(entry game.LeaderboardEntry, won bool) error {
+	s.usersMutex.Lock()
+	defer s.usersMutex.Unlock()
+	s.leaderboardMutex.Lock()
+	defer s.leaderboardMutex.Unlock()
+
+	user, exists := s.users[entry.PlayerID]
+	if !exists {
+		return models.ErrUserNotFound
+	}
+	if err := s.insertLeaderboardEntry(entry); err != nil {
+		return err
+	}
+
+	updated := cloneUser(user)
+	updated.RecordGame(won, entry.Score, entry.SubmittedAt)
+	s.users[updated.ID] = updated
+	return nil
+}
+
+// insertLeaderboardEntry insère un score, le verrou du classement étant tenu
+func (s *Store) insertLeaderboardEntry(entry game.LeaderboardEntry) error {
+	if _, exists := s.leaderboardIDs[entry.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.leaderboardGames[entry.GameID]; entry.GameID != "" && exists {
+		return game.ErrScoreAlreadySubmitted
+	}
+
+	s.leaderboardIDs[entry.ID] = struct{}{}
+	if entry.GameID != "" {
+		s.leaderboardGames[entry.GameID] = struct{}{}
+	}
+	s.leaderboard = insertRanked(s.leaderboard, entry)
+	s.leaderboardByTime = insertChronological(s.leaderboardByTime, entry)
+	s.leaderboardByDifficulty[entry.Difficulty] = insertRanked(s.leaderboardByDifficulty[entry.Difficulty], entry)
+	return nil
+}
+
+// ListLeaderboardEntries retourne une copie d'une page du classement
+func (s *Store) ListLeaderboardEntries(query game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error) {
+	s.leaderboardMutex.RLock()
+	defer s.leaderboardMutex.RUnlock()
+
+	entries := s.rankedEntries(query)
+
+	// Copier la page pour éviter les modifications concurrentes
+	return append([]game.LeaderboardEntry{}, page(entries, query.Offset, query.Limit)...), len(entries), nil
+}
+
+// PlayerStanding retourne la meilleure entrée d'un joueur et son rang
+func (s *Store) PlayerStanding(playerID string, query game.LeaderboardQuery) (*game.LeaderboardEntry, int, error) {
+	s.leaderboardMutex.RLock()
+	defer s.leaderboardMutex.RUnlock()
+
+	return standing(s.rankedEntries(query), playerID)
+}
+
+// rankedEntries retourne les scores correspondant à la requête dans l'ordre du
+// classement (verrou déjà pris). Le résultat peut partager le stockage interne.
+func (s *Store) rankedEntries(query game.LeaderboardQuery) []game.LeaderboardEntry {
+	var entries []game.LeaderboardEntry
+	if query.From.IsZero() && query.To.IsZero() {
+		entries = s.leaderboard
+		if query.Difficulty != "" {
+			entries = s.leaderboardByDifficulty[query.Difficulty]
+		}
+		if query.Language != "" || query.Category != "" || query.Daily != "" {
+			entries = filterEntries(entries, query)
+		}
+	} else {
+		// Seuls les scores de la période sont copiés puis classés
+		entries = filterEntries(s.entriesBetween(query.From, query.To), query)
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].RanksBefore(entries[j])
+		})
+	}
+
+	if query.BestPerPlayer {
+		entries = bestPerPlayer(entries)
+	}
+	return entries
+}
+
+// filterEntries retourne une copie des scores correspondant aux filtres de
+// difficulté, de langue, de catégorie et de défi quotidien de la requête, dans leur ordre d'origine
+func filterEntries(entries []game.LeaderboardEntry, query game.LeaderboardQuery) []game.LeaderboardEntry {
+	var filtered []game.LeaderboardEntry
+	for _, entry := range entries {
+		if (query.Difficulty == "" || entry.Difficulty == query.Difficulty) &&
+			(query.Language == "" || entry.Language == query.Language) &&
+			(query.Category == "" || entry.Category == query.Category) &&
+			(query.Daily == "" || entry.Daily == query.Daily) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// entriesBetween retourne les scores soumis dans [from, to) par recherche
+// dichotomique (verrou déjà pris, bornes nulles = sans limite)
+func (s *Store) entriesBetween(from, to time.Time) []game.LeaderboardEntry {
+	entries := s.leaderboardByTime
+	start := sort.Search(len(entries), func(i int) bool {
+		return !entries[i].SubmittedAt.Before(from)
+	})
+	end := len(entries)
+	if !to.IsZero() {
+		end = sort.Search(len(entries), func(i int) bool {
+			return !entries[i].SubmittedAt.Before(to)
+		})
+	}
+	if end < start {
+		return nil
+	}
+	return entries[start:end]
+}
+
+// CreateSeason enregistre une nouvelle saison
+func (s *Store) CreateSeason(season *game.Season) error {
+	s.seasonsMutex.Lock()
+	defer s.seasonsMutex.Unlock()
+
+	if _, exists := s.seasons[season.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.seasonNames[season.Name]; exists {
+		return game.ErrSeasonNameTaken
+	}
+
+	c := *season
+	s.seasons[c.ID] = &c
+	s.seasonNames[c.Name] = c.ID
+	return nil
+}
+
+// GetSeason récupère une saison par son ID
+func (s *Store) GetSeason(id string) (*game.Season, error) {
+	s.seasonsMutex.RLock()
+	defer s.seasonsMutex.RUnlock()
+
+	season, exists := s.seasons[id]
+	if !exists {
+		return nil, game.ErrSeasonNotFound
+	}
+
+	c := *season
+	return &c, nil
+}
+
+// ListSeasons retourne toutes les saisons, les plus récentes en premier
+func (s *Store) ListSeasons() ([]game.Season, error) {
+	s.seasonsMutex.RLock()
+	defer s.seasonsMutex.RUnlock()
+
+	seasons := []game.Season{}
+	for _, season := range s.seasons {
+		seasons = append(seasons, *season)
+	}
+
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].StartsAt.After(seasons[j].StartsAt)
+	})
+	return seasons, nil
+}
+
+// ArchiveSeason fige le classement d'une saison
+func (s *Store) ArchiveSeason(id string, archivedAt time.Time) error {
+	s.seasonsMutex.Lock()
+	defer s.seasonsMutex.Unlock()
+
+	season, exists := s.seasons[id]
+	if !exists {
+		return game.ErrSeasonNotFound
+	}
+	if !season.ArchivedAt.IsZero() {
+		return game.ErrSeasonArchived
+	}
+
+	s.leaderboardMutex.RLock()
+	entries := append([]game.LeaderboardEntry{}, s.entriesBetween(season.StartsAt, season.EndsAt)...)
+	s.leaderboardMutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].RanksBefore(entries[j])
+	})
+
+	s.seasonEntries[id] = entries
+	season.ArchivedAt = archivedAt
+	return nil
+}
+
+// ListSeasonEntries retourne une copie d'une page du classement archivé d'une saison
+func (s *Store) ListSeasonEntries(seasonID string, query game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error) {
+	s.seasonsMutex.RLock()
+	defer s.seasonsMutex.RUnlock()
+
+	entries := s.seasonEntries[seasonID]
+	if query.Difficulty != "" || query.Language != "" || query.Category != "" || query.Daily != "" {
+		entries = filterEntries(entries, query)
+	}
+	if query.BestPerPlayer {
+		entries = bestPerPlayer(entries)
+	}
+
+	return append([]game.LeaderboardEntry{}, page(entries, query.Offset, query.Limit)...), len(entries), nil
+}
+
+// CreateUser enregistre un nouvel utilisateur
+func (s *Store) CreateUser(user *models.User) error {
+	s.usersMutex.Lock()
+	defer s.usersMutex.Unlock()
+
+	if _, exists := s.users[user.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.usersByName[user.Name]; exists {
+		return models.ErrUsernameTaken
+	}
+
+	s.users[user.ID] = cloneUser(user)
+	s.usersByName[user.Name] = user.ID
+	return nil
+}
+
+// GetUser récupère un utilisateur par son ID
+func (s *Store) GetUser(id string) (*models.User, error) {
+	s.usersMutex.RLock()
+	defer s.usersMutex.RUnlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return nil, models.ErrUserNotFound
+	}
+	return cloneUser(user), nil
+}
+
+// GetUserByName récupère un utilisateur par son nom
+func (s *Store) GetUserByName(username string) (*models.User, error) {
+	s.usersMutex.RLock()
+	defer s.usersMutex.RUnlock()
+
+	id, exists := s.usersByName[username]
+	if !exists {
+		return nil, models.ErrUserNotFound
+	}
+	return cloneUser(s.users[id]), nil
+}
+
+// UpdateUser applique fn à une copie de l'utilisateur puis l'enregistre
+func (s *Store) UpdateUser(id string, fn func(*models.User) error) (*models.User, error) {
+	s.usersMutex.Lock()
+	defer s.usersMutex.Unlock()
+
+	user, exists := s.users[id]
+	if !exists {
+		return nil, models.ErrUserNotFound
+	}
+
+	updated := cloneUser(user)
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+
+	s.users[id] = updated
+	return cloneUser(updated), nil
+}
+
+// CreateSession enregistre une nouvelle session
+func (s *Store) CreateSession(session *models.Session) error {
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+
+	if _, exists := s.sessions[session.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.sessionsByAccess[session.AccessTokenHash]; exists {
+		return utils.ErrDuplicateID
+	}
+	if _, exists := s.sessionsByRefresh[session.RefreshTokenHash]; exists {
+		return utils.ErrDuplicateID
+	}
+
+	c := *session
+	s.sessions[c.ID] = &c
+	s.sessionsByAccess[c.AccessTokenHash] = c.ID
+	s.sessionsByRefresh[c.RefreshTokenHash] = c.ID
+	return nil
+}
+
+// GetSessionByAccessToken récupère une session par l'empreinte de son token d'accès
+func (s *Store) GetSessionByAccessToken(tokenHash string) (*models.Session, error) {
+	s.sessionsMutex.RLock()
+	defer s.sessionsMutex.RUnlock()
+
+	return s.sessionByIndex(s.sessionsByAccess, tokenHash)
+}
+
+// GetSessionByRefreshToken récupère une session par l'empreinte de son token de rafraîchissement
+func (s *Store) GetSessionByRefreshToken(tokenHash string) (*models.Session, error) {
+	s.sessionsMutex.RLock()
+	defer s.sessionsMutex.RUnlock()
+
+	return s.sessionByIndex(s.sessionsByRefresh, tokenHash)
+}
+
+// RotateSession remplace les tokens d'une session si le token de rafraîchissement n'a pas changé
+func (s *Store) RotateSession(session *models.Session, oldRefreshHash string) error {
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+
+	current, exists := s.sessions[session.ID]
+	if !exists || current.RefreshTokenHash != oldRefreshHash {
+		return models.ErrInvalidToken
+	}
+
+	delete(s.sessionsByAccess, current.AccessTokenHash)
+	delete(s.sessionsByRefresh, current.RefreshTokenHash)
+
+	c := *session
+	s.sessions[c.ID] = &c
+	s.sessionsByAccess[c.AccessTokenHash] = c.ID
+	s.sessionsByRefresh[c.RefreshTokenHash] = c.ID
+	return nil
+}
+
+// ListUserSessions retourne les sessions d'un utilisateur, les plus récentes en premier
+func (s *Store) ListUserSessions(userID string) ([]models.Session, error) {
+	s.sessionsMutex.RLock()
+	defer s.sessionsMutex.RUnlock()
+
+	sessions := []models.Session{}
+	for _, session := range s.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, *session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// DeleteSession supprime une session
+func (s *Store) DeleteSession(id string) error {
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+
+	if _, exists := s.sessions[id]; !exists {
+		return models.ErrSessionNotFound
+	}
+
+	s.deleteSession(id)
+	return nil
+}
+
+// DeleteUserSessions supprime toutes les sessions d'un utilisateur
+func (s *Store) DeleteUserSessions(userID string) error {
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+
+	for id, session := range s.sessions {
+		if session.UserID == userID {
+			s.deleteSession(id)
+		}
+	}
+	return nil
+}
+
+// DeleteExpiredSessions supprime les sessions dont le token de rafraîchissement a expiré
+func (s *Store) DeleteExpiredSessions(now time.Time) (int, error) {
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+
+	deleted := 0
+	for id, session := range s.sessions {
+		if !now.Before(session.RefreshExpiresAt) {
+			s.deleteSession(id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// sessionByIndex résout une empreinte de token via l'index donné (verrou déjà pris)
+func (s *Store) sessionByIndex(index map[string]string, tokenHash string) (*models.Session, error) {
+	id, exists := index[tokenHash]
+	if !exists {
+		return nil, models.ErrInvalidToken
+	}
+
+	c := *s.sessions[id]
+	return &c, nil
+}
+
+// deleteSession retire une session et ses index (verrou déjà pris)
+func (s *Store) deleteSession(id string) {
+	session := s.sessions[id]
+	delete(s.sessionsByAccess, session.AccessTokenHash)
+	delete(s.sessionsByRefresh, session.RefreshTokenHash)
+	delete(s.sessions, id)
+}
+
+// insertRanked insère une entrée dans un classement trié en conservant l'ordre
+func insertRanked(entries []game.LeaderboardEntry, entry game.LeaderboardEntry) []game.LeaderboardEntry {
+	i := sort.Search(len(entries), func(i int) bool {
+		return entry.RanksBefore(entries[i])
+	})
+	return slices.Insert(entries, i, entry)
+}
+
+// insertChronological insère une entrée dans une liste triée par date de soumission
+func insertChronological(entries []game.LeaderboardEntry, entry game.LeaderboardEntry) []game.LeaderboardEntry {
+	i := sort.Search(len(entries), func(i int) bool {
+		return entry.SubmittedAt.Before(entries[i].SubmittedAt)
+	})
+	return slices.Insert(entries, i, entry)
+}
+
+// bestPerPlayer ne garde que la première entrée de chaque joueur d'un classement
+// trié, c'est-à-dire sa meilleure
+func bestPerPlayer(entries []game.LeaderboardEntry) []game.LeaderboardEntry {
+	seen := make(map[string]struct{})
+	var best []game.LeaderboardEntry
+	for _, entry := range entries {
+		if _, exists := seen[entry.PlayerID]; !exists {
+			seen[entry.PlayerID] = struct{}{}
+			best = append(best, entry)
+		}
+	}
+	return best
+}
+
+// standing cherche la meilleure entrée d'un joueur dans un classement trié
+func standing(entries []game.LeaderboardEntry, playerID string) (*game.LeaderboardEntry, int, error) {
+	for i, entry := range entries {
+		if entry.PlayerID == playerID {
+			return &entry, i + 1, nil
+		}
+	}
+	return nil, 0, game.ErrPlayerNotRanked
+}
+
+// page découpe entries selon offset et limit
+func page(entries []game.LeaderboardEntry, offset, limit int) []game.LeaderboardEntry {
+	if offset >= len(entries) {
+		return nil
+	}
+	entries = entries[offset:]
+	if limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// cloneGame copie une partie pour que l'appelant ne partage pas l'état stocké
+func cloneGame(g *game.Game) *game.Game {
+	c := *g
+	c.Guesses = append([]string{}, g.Guesses...)
+	c.WordGuesses = append([]string{}, g.WordGuesses...)
+	return &c
+}
+
+// cloneUser copie un utilisateur pour que l'appelant ne partage pas l'état stocké
+func cloneUser(u *models.User) *models.User {
+	c := *u
+	c.Roles = append([]string{}, u.Roles...)
+	return &c
+}
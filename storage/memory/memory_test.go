@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/storage/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return New()
+	})
+}
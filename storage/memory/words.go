@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// CreateWord enregistre un nouveau mot et l'entrée de journal de sa création
+func (s *Store) CreateWord(word *game.WordEntry, audit game.WordAudit) error {
+	return s.SaveWords([]game.WordChange{{Word: word, Audit: audit, New: true}})
+}
+
+// SaveWords enregistre un lot de créations et de modifications de mots, avec
+// leurs entrées de journal. Tout le lot est vérifié avant d'être appliqué.
+func (s *Store) SaveWords(changes []game.WordChange) error {
+	s.wordsMutex.Lock()
+	defer s.wordsMutex.Unlock()
+
+	// Clés et IDs tels qu'ils seront au fil du lot
+	keys := maps.Clone(s.wordKeys)
+	ids := map[string]string{} // ID -> clé des mots touchés par le lot
+	auditIDs := map[string]bool{}
+	for _, change := range changes {
+		word := change.Word
+		previous, touched := ids[word.ID]
+		existing, stored := s.words[word.ID]
+		if change.New && (stored || touched) {
+			return utils.ErrDuplicateID
+		}
+		if !change.New && !stored && !touched {
+			return game.ErrWordNotFound
+		}
+		if _, exists := s.wordAuditIDs[change.Audit.ID]; exists || auditIDs[change.Audit.ID] {
+			return utils.ErrDuplicateID
+		}
+		key := wordKey(word)
+		if id, exists := keys[key]; exists && id != word.ID {
+			return game.ErrWordExists
+		}
+
+		if touched {
+			delete(keys, previous)
+		} else if stored {
+			delete(keys, wordKey(existing))
+		}
+		keys[key] = word.ID
+		ids[word.ID] = key
+		auditIDs[change.Audit.ID] = true
+	}
+
+	for _, change := range changes {
+		s.words[change.Word.ID] = cloneWord(change.Word)
+		s.addWordAudit(change.Audit)
+	}
+	s.wordKeys = keys
+	return nil
+}
+
+// GetWord récupère un mot par son ID
+func (s *Store) GetWord(id string) (*game.WordEntry, error) {
+	s.wordsMutex.RLock()
+	defer s.wordsMutex.RUnlock()
+
+	word, exists := s.words[id]
+	if !exists {
+		return nil, game.ErrWordNotFound
+	}
+	return cloneWord(word), nil
+}
+
+// FindWord récupère un mot par sa langue et sa clé de comparaison
+func (s *Store) FindWord(language, key string) (*game.WordEntry, error) {
+	s.wordsMutex.RLock()
+	defer s.wordsMutex.RUnlock()
+
+	id, exists := s.wordKeys[language+"/"+key]
+	if !exists {
+		return nil, game.ErrWordNotFound
+	}
+	return cloneWord(s.words[id]), nil
+}
+
+// ListWords retourne une copie des mots correspondant au filtre
+func (s *Store) ListWords(filter game.WordFilter) ([]game.WordEntry, error) {
+	s.wordsMutex.RLock()
+	defer s.wordsMutex.RUnlock()
+
+	search := game.WordKey(filter.Search)
+	words := []game.WordEntry{}
+	for _, word := range s.words {
+		if (filter.Language != "" && word.Language != filter.Language) ||
+			(filter.Difficulty != "" && word.Difficulty != filter.Difficulty) ||
+			(filter.Category != "" && word.Category != filter.Category) ||
+			(filter.Enabled != nil && word.Enabled != *filter.Enabled) ||
+			!strings.Contains(game.WordKey(word.Word), search) {
+			continue
+		}
+		words = append(words, *cloneWord(word))
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Language != words[j].Language {
+			return words[i].Language < words[j].Language
+		}
+		return words[i].Word < words[j].Word
+	})
+	return words, nil
+}
+
+// UpdateWord remplace un mot existant et enregistre l'entrée de journal
+func (s *Store) UpdateWord(word *game.WordEntry, audit game.WordAudit) error {
+	return s.SaveWords([]game.WordChange{{Word: word, Audit: audit}})
+}
+
+// DeleteWord supprime un mot et enregistre l'entrée de journal
+func (s *Store) DeleteWord(id string, audit game.WordAudit) error {
+	s.wordsMutex.Lock()
+	defer s.wordsMutex.Unlock()
+
+	existing, exists := s.words[id]
+	if !exists {
+		return game.ErrWordNotFound
+	}
+	if _, exists := s.wordAuditIDs[audit.ID]; exists {
+		return utils.ErrDuplicateID
+	}
+
+	delete(s.wordKeys, wordKey(existing))
+	delete(s.words, id)
+	s.addWordAudit(audit)
+	return nil
+}
+
+// ListWordAudit retourne les dernières entrées du journal, les plus récentes en premier
+func (s *Store) ListWordAudit(wordID string, limit int) ([]game.WordAudit, error) {
+	s.wordsMutex.RLock()
+	defer s.wordsMutex.RUnlock()
+
+	entries := []game.WordAudit{}
+	for i := len(s.wordAudit) - 1; i >= 0 && len(entries) < limit; i-- {
+		if wordID == "" || s.wordAudit[i].WordID == wordID {
+			entries = append(entries, s.wordAudit[i])
+		}
+	}
+	return entries, nil
+}
+
+// CountWordAudit retourne le nombre d'entrées du journal des mots
+func (s *Store) CountWordAudit() (int, error) {
+	s.wordsMutex.RLock()
+	defer s.wordsMutex.RUnlock()
+	return len(s.wordAudit), nil
+}
+
+// addWordAudit ajoute une entrée au journal (verrou déjà pris)
+func (s *Store) addWordAudit(audit game.WordAudit) {
+	if audit.Before != nil {
+		audit.Before = cloneWord(audit.Before)
+	}
+	if audit.After != nil {
+		audit.After = cloneWord(audit.After)
+	}
+	s.wordAudit = append(s.wordAudit, audit)
+	s.wordAuditIDs[audit.ID] = struct{}{}
+}
+
+// wordKey retourne la clé d'unicité d'un mot dans sa langue
+func wordKey(word *game.WordEntry) string {
+	return word.Language + "/" + game.WordKey(word.Word)
+}
+
+// cloneWord copie un mot pour que l'appelant ne partage pas l'état stocké
+func cloneWord(w *game.WordEntry) *game.WordEntry {
+	c := *w
+	c.Tags = append([]string{}, w.Tags...)
+	return &c
+}
@@ -0,0 +1,19 @@
+-- Remplace les tokens permanents par des sessions avec expiration et rafraîchissement.
+-- Les anciens tokens sont abandonnés : les utilisateurs doivent se reconnecter.
+
+DROP TABLE auth_tokens;
+
+CREATE TABLE sessions (
+    id                 TEXT PRIMARY KEY,
+    user_id            TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    access_token_hash  TEXT NOT NULL UNIQUE,
+    refresh_token_hash TEXT NOT NULL UNIQUE,
+    access_expires_at  BIGINT NOT NULL,
+    refresh_expires_at BIGINT NOT NULL,
+    created_at         BIGINT NOT NULL,
+    refreshed_at       BIGINT NOT NULL,
+    user_agent         TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX sessions_user_id ON sessions (user_id);
+CREATE INDEX sessions_refresh_expires_at ON sessions (refresh_expires_at);
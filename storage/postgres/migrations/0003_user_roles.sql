@@ -0,0 +1,3 @@
+-- Rôles des utilisateurs (tableau JSON, par exemple ["admin"])
+
+ALTER TABLE users ADD COLUMN roles TEXT NOT NULL DEFAULT '[]';
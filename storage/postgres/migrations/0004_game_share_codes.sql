@@ -0,0 +1,5 @@
+-- Code court facultatif permettant de partager une partie (NULL si absent)
+
+ALTER TABLE games ADD COLUMN share_code TEXT;
+
+CREATE UNIQUE INDEX games_share_code ON games (share_code);
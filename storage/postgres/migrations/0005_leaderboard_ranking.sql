@@ -0,0 +1,16 @@
+-- Classement trié : identifiants ULID, nombre d'erreurs et date de soumission
+-- servent à départager les scores égaux. Les anciens identifiants numériques
+-- sont complétés par des zéros pour rester triés avant les ULID ; les anciennes
+-- entrées n'ont ni erreurs ni date connues (0).
+
+ALTER TABLE leaderboard_entries ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE leaderboard_entries ALTER COLUMN id TYPE TEXT USING lpad(id::text, 26, '0');
+DROP SEQUENCE leaderboard_entries_id_seq;
+
+ALTER TABLE leaderboard_entries ADD COLUMN wrong_guesses INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard_entries ADD COLUMN submitted_at BIGINT NOT NULL DEFAULT 0;
+
+DROP INDEX leaderboard_entries_difficulty;
+
+CREATE INDEX leaderboard_entries_rank ON leaderboard_entries (score DESC, wrong_guesses, submitted_at, id);
+CREATE INDEX leaderboard_entries_difficulty_rank ON leaderboard_entries (difficulty, score DESC, wrong_guesses, submitted_at, id);
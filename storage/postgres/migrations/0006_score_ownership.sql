@@ -0,0 +1,9 @@
+-- Rattache chaque partie au joueur qui l'a créée (NULL si anonyme) et chaque
+-- score à sa partie, pour qu'une partie ne soit soumise qu'une seule fois.
+-- Les scores enregistrés avant cette migration n'ont pas de partie connue (NULL).
+
+ALTER TABLE games ADD COLUMN player_id TEXT;
+
+ALTER TABLE leaderboard_entries ADD COLUMN game_id TEXT;
+
+CREATE UNIQUE INDEX leaderboard_entries_game_id ON leaderboard_entries (game_id);
@@ -0,0 +1,4 @@
+-- Index des dates de soumission : les classements journaliers, hebdomadaires
+-- et mensuels ne lisent que les scores de leur période
+
+CREATE INDEX leaderboard_entries_submitted_at ON leaderboard_entries (submitted_at);
@@ -0,0 +1,28 @@
+-- Saisons de compétition et copie figée de leur classement final.
+-- archived_at vaut 0 tant que la saison n'est pas archivée.
+
+CREATE TABLE seasons (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    starts_at   BIGINT NOT NULL,
+    ends_at     BIGINT NOT NULL,
+    archived_at BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE season_entries (
+    season_id          TEXT NOT NULL REFERENCES seasons (id) ON DELETE CASCADE,
+    id                 TEXT NOT NULL,
+    game_id            TEXT,
+    player_id          TEXT NOT NULL,
+    player_name        TEXT NOT NULL,
+    score              INTEGER NOT NULL,
+    word_length        INTEGER NOT NULL,
+    remaining_attempts INTEGER NOT NULL,
+    wrong_guesses      INTEGER NOT NULL,
+    difficulty         TEXT NOT NULL,
+    submitted_at       BIGINT NOT NULL,
+    PRIMARY KEY (season_id, id)
+);
+
+CREATE INDEX season_entries_rank ON season_entries (season_id, score DESC, wrong_guesses, submitted_at, id);
+CREATE INDEX season_entries_difficulty_rank ON season_entries (season_id, difficulty, score DESC, wrong_guesses, submitted_at, id);
@@ -0,0 +1,3 @@
+-- Mots ou phrases proposés en entier pendant une partie
+
+ALTER TABLE games ADD COLUMN word_guesses TEXT NOT NULL DEFAULT '[]'; -- tableau JSON
@@ -0,0 +1,27 @@
+-- Défi quotidien : un mot commun par jour et par langue, et la partie que
+-- chaque joueur y a jouée (une seule). Les parties et les scores gardent le
+-- jour du défi pour le classement quotidien.
+
+CREATE TABLE daily_challenges (
+    day        TEXT NOT NULL,
+    language   TEXT NOT NULL,
+    word       TEXT NOT NULL,
+    hint       TEXT NOT NULL,
+    difficulty TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (language, day)
+);
+
+CREATE TABLE daily_plays (
+    day       TEXT NOT NULL,
+    language  TEXT NOT NULL,
+    player_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    game_id   TEXT NOT NULL,
+    PRIMARY KEY (language, day, player_id)
+);
+
+ALTER TABLE games ADD COLUMN daily TEXT NOT NULL DEFAULT '';
+ALTER TABLE leaderboard_entries ADD COLUMN daily TEXT NOT NULL DEFAULT '';
+ALTER TABLE season_entries ADD COLUMN daily TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX leaderboard_entries_daily_rank ON leaderboard_entries (daily, score DESC, wrong_guesses, submitted_at, id);
@@ -0,0 +1,4 @@
+-- Graine du tirage du mot de chaque partie : recréer une partie avec la même
+-- graine (et le même catalogue) tire le même mot
+
+ALTER TABLE games ADD COLUMN seed BIGINT NOT NULL DEFAULT 0;
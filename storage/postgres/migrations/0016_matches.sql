@@ -0,0 +1,33 @@
+-- Courses multijoueurs : un salon rejoint avec un code court, puis une partie
+-- par joueur sur le même mot. Le rang final de chaque joueur est gardé avec la
+-- course une fois terminée.
+
+CREATE TABLE matches (
+    id          TEXT PRIMARY KEY,
+    code        TEXT NOT NULL UNIQUE,
+    host_id     TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    difficulty  TEXT NOT NULL,
+    language    TEXT NOT NULL,
+    category    TEXT NOT NULL DEFAULT '',
+    matching    TEXT NOT NULL,
+    word        TEXT NOT NULL,
+    hint        TEXT NOT NULL,
+    winner_id   TEXT NOT NULL DEFAULT '',
+    created_at  BIGINT NOT NULL,
+    starts_at   BIGINT NOT NULL DEFAULT 0,
+    finished_at BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE match_players (
+    match_id    TEXT NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    player_id   TEXT NOT NULL,
+    player_name TEXT NOT NULL,
+    game_id     TEXT NOT NULL DEFAULT '',
+    joined_at   BIGINT NOT NULL,
+    final_rank  INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (match_id, player_id)
+);
+
+ALTER TABLE games ADD COLUMN match_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Mots importés du répertoire de fichiers de mots : ceux qui en sont retirés
+-- sont désactivés au chargement suivant
+
+ALTER TABLE words ADD COLUMN from_files BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Graine du tirage du mot de chaque course, comme pour les parties
+
+ALTER TABLE matches ADD COLUMN seed BIGINT NOT NULL DEFAULT 0;
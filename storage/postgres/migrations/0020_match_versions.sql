@@ -0,0 +1,4 @@
+-- Version de chaque course, incrémentée à chaque enregistrement comme celle des
+-- parties : deux serveurs ne peuvent pas écraser les modifications l'un de l'autre
+
+ALTER TABLE matches ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
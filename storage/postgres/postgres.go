@@ -0,0 +1,59 @@
+// Package postgres fournit un stockage persistant dans une base PostgreSQL.
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+
+	"github.com/N95Ryan/8bit-hangman-back/storage/sqlstore"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib" // Enregistre le driver "pgx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Dialect décrit la syntaxe PostgreSQL pour sqlstore. Les migrations sont
+// sérialisées par un verrou consultatif pour démarrer plusieurs serveurs en parallèle.
+var Dialect = sqlstore.Dialect{
+	NumberedPlaceholders: true,
+	ForUpdate:            " FOR UPDATE",
+	ForShare:             " FOR SHARE",
+	LockMigrations:       "SELECT pg_advisory_lock(82024001)",
+	UnlockMigrations:     "SELECT pg_advisory_unlock(82024001)",
+	IsUniqueViolation:    isUniqueViolation,
+}
+
+// Open se connecte à la base décrite par dsn et applique les migrations
+func Open(dsn string) (*sqlstore.Store, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	migrations, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := sqlstore.Migrate(db, Dialect, migrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return sqlstore.New(db, Dialect), nil
+}
+
+// isUniqueViolation indique si l'erreur provient d'une contrainte UNIQUE (code 23505)
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
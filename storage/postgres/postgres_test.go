@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"database/sql"
+	"io/fs"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/N95Ryan/8bit-hangman-back/storage/storetest"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Les tests d'intégration utilisent la base désignée par HANGMAN_TEST_DATABASE_URL
+// et sont ignorés si elle n'est pas définie. Chaque test travaille dans un schéma
+// temporaire, supprimé à la fin du test.
+const testDSNEnv = "HANGMAN_TEST_DATABASE_URL"
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		dsn, _ := testSchema(t)
+		store, err := Open(dsn)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+// TestMigrate démarre plusieurs serveurs en parallèle sur une base vide : le
+// verrou consultatif doit appliquer chaque migration une seule fois
+func TestMigrate(t *testing.T) {
+	dsn, admin := testSchema(t)
+
+	const servers = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, servers)
+	for range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store, err := Open(dsn)
+			if err == nil {
+				store.Close()
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Open: %v", err)
+		}
+	}
+
+	// Rouvrir une base à jour n'applique plus rien
+	store, err := Open(dsn)
+	if err != nil {
+		t.Fatalf("Open on a migrated database: %v", err)
+	}
+	store.Close()
+
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix, _, _ := strings.Cut(entries[len(entries)-1].Name(), "_")
+	latest, err := strconv.Atoi(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var applied, version int
+	if err := admin.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&applied, &version); err != nil {
+		t.Fatalf("reading schema_migrations: %v", err)
+	}
+	if applied != len(entries) || version != latest {
+		t.Errorf("schema_migrations has %d migrations up to %d, want %d up to %d", applied, version, len(entries), latest)
+	}
+}
+
+// testSchema crée un schéma vide et retourne le DSN qui l'utilise par défaut,
+// ainsi qu'une connexion à ce schéma pour les vérifications du test
+func testSchema(t *testing.T) (string, *sql.DB) {
+	t.Helper()
+	base := os.Getenv(testDSNEnv)
+	if base == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	root, err := sql.Open("pgx", base)
+	if err != nil {
+		t.Fatalf("connecting to %s: %v", testDSNEnv, err)
+	}
+	t.Cleanup(func() { root.Close() })
+
+	schema := "hangman_test_" + strings.ToLower(utils.NewID())
+	if _, err := root.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := root.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("dropping schema %s: %v", schema, err)
+		}
+	})
+
+	dsn := withSearchPath(t, base, schema)
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return dsn, db
+}
+
+// withSearchPath ajoute le paramètre search_path à un DSN sous forme d'URL ou
+// de paires clé=valeur
+func withSearchPath(t *testing.T, dsn, schema string) string {
+	t.Helper()
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", testDSNEnv, err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,42 @@
+-- Schéma initial : utilisateurs, tokens, parties et classement
+-- Les dates sont stockées en nanosecondes Unix
+
+CREATE TABLE users (
+    id           TEXT PRIMARY KEY,
+    name         TEXT NOT NULL UNIQUE,
+    email        TEXT NOT NULL,
+    password     TEXT NOT NULL,
+    games_played INTEGER NOT NULL DEFAULT 0,
+    games_won    INTEGER NOT NULL DEFAULT 0,
+    high_score   INTEGER NOT NULL DEFAULT 0,
+    created_at   INTEGER NOT NULL,
+    updated_at   INTEGER NOT NULL
+);
+
+CREATE TABLE auth_tokens (
+    token   TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE games (
+    id         TEXT PRIMARY KEY,
+    word       TEXT NOT NULL,
+    guesses    TEXT NOT NULL, -- tableau JSON des lettres proposées
+    remaining  INTEGER NOT NULL,
+    status     TEXT NOT NULL,
+    score      INTEGER NOT NULL,
+    difficulty TEXT NOT NULL,
+    hint       TEXT NOT NULL
+);
+
+CREATE TABLE leaderboard_entries (
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id          TEXT NOT NULL,
+    player_name        TEXT NOT NULL,
+    score              INTEGER NOT NULL,
+    word_length        INTEGER NOT NULL,
+    remaining_attempts INTEGER NOT NULL,
+    difficulty         TEXT NOT NULL
+);
+
+CREATE INDEX leaderboard_entries_difficulty ON leaderboard_entries (difficulty);
@@ -0,0 +1,28 @@
+-- Classement trié : identifiants ULID, nombre d'erreurs et date de soumission
+-- servent à départager les scores égaux. SQLite ne sait pas changer le type
+-- d'une clé primaire, la table est donc reconstruite. Les anciens identifiants
+-- numériques sont complétés par des zéros pour rester triés avant les ULID ;
+-- les anciennes entrées n'ont ni erreurs ni date connues (0).
+
+CREATE TABLE leaderboard_entries_new (
+    id                 TEXT PRIMARY KEY,
+    player_id          TEXT NOT NULL,
+    player_name        TEXT NOT NULL,
+    score              INTEGER NOT NULL,
+    word_length        INTEGER NOT NULL,
+    remaining_attempts INTEGER NOT NULL,
+    wrong_guesses      INTEGER NOT NULL DEFAULT 0,
+    difficulty         TEXT NOT NULL,
+    submitted_at       INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO leaderboard_entries_new (id, player_id, player_name, score, word_length, remaining_attempts, difficulty)
+SELECT printf('%026d', id), player_id, player_name, score, word_length, remaining_attempts, difficulty
+FROM leaderboard_entries;
+
+DROP TABLE leaderboard_entries;
+
+ALTER TABLE leaderboard_entries_new RENAME TO leaderboard_entries;
+
+CREATE INDEX leaderboard_entries_rank ON leaderboard_entries (score DESC, wrong_guesses, submitted_at, id);
+CREATE INDEX leaderboard_entries_difficulty_rank ON leaderboard_entries (difficulty, score DESC, wrong_guesses, submitted_at, id);
@@ -0,0 +1,3 @@
+-- Mode de comparaison des lettres : "fold" (accents ignorés) ou "strict"
+
+ALTER TABLE games ADD COLUMN matching TEXT NOT NULL DEFAULT 'fold';
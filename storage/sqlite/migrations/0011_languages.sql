@@ -0,0 +1,8 @@
+-- Langue du catalogue de mots d'une partie, reprise dans ses scores pour que
+-- chaque langue ait son propre classement
+
+ALTER TABLE games ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE leaderboard_entries ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE season_entries ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+
+CREATE INDEX leaderboard_entries_language_rank ON leaderboard_entries (language, score DESC, wrong_guesses, submitted_at, id);
@@ -0,0 +1,32 @@
+-- Mots gérés par les administrateurs et journal de leurs modifications.
+-- word_key est le mot sans accents ni casse : un mot n'existe qu'une fois par langue.
+
+CREATE TABLE words (
+    id         TEXT PRIMARY KEY,
+    word       TEXT NOT NULL,
+    word_key   TEXT NOT NULL,
+    hint       TEXT NOT NULL,
+    difficulty TEXT NOT NULL,
+    language   TEXT NOT NULL,
+    tags       TEXT NOT NULL DEFAULT '[]', -- tableau JSON
+    enabled    BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX words_language_key ON words (language, word_key);
+
+-- Pas de clé étrangère vers words : le journal survit à la suppression du mot
+CREATE TABLE word_audit (
+    id           TEXT PRIMARY KEY,
+    word_id      TEXT NOT NULL,
+    action       TEXT NOT NULL,
+    user_id      TEXT,
+    user_name    TEXT NOT NULL,
+    before_state TEXT, -- mot avant la modification, en JSON
+    after_state  TEXT, -- mot après la modification, en JSON
+    created_at   BIGINT NOT NULL
+);
+
+CREATE INDEX word_audit_created_at ON word_audit (created_at);
+CREATE INDEX word_audit_word_id ON word_audit (word_id, created_at);
@@ -0,0 +1,9 @@
+-- Catégorie des mots (consoles, personnages...), choisie à la création d'une
+-- partie et reprise dans ses scores pour les classements par catégorie
+
+ALTER TABLE words ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE games ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE leaderboard_entries ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE season_entries ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX leaderboard_entries_category_rank ON leaderboard_entries (category, score DESC, wrong_guesses, submitted_at, id);
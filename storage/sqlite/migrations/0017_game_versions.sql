@@ -0,0 +1,5 @@
+-- Version de chaque partie, incrémentée à chaque enregistrement : la mise à
+-- jour n'aboutit que si la partie n'a pas été enregistrée depuis sa lecture,
+-- ce qui protège les propositions quand plusieurs serveurs partagent la base
+
+ALTER TABLE games ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,62 @@
+// Package sqlite fournit un stockage persistant dans un fichier SQLite.
+// Il utilise le driver pur Go modernc.org/sqlite et ne nécessite donc pas cgo.
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+	"net/url"
+
+	"github.com/N95Ryan/8bit-hangman-back/storage/sqlstore"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Dialect décrit la syntaxe SQLite pour sqlstore
+var Dialect = sqlstore.Dialect{
+	IsUniqueViolation: isUniqueViolation,
+}
+
+// Open ouvre (ou crée) la base SQLite au chemin donné et applique les migrations
+func Open(path string) (*sqlstore.Store, error) {
+	// Les transactions prennent le verrou d'écriture dès le BEGIN pour éviter
+	// les échecs SQLITE_BUSY lors d'une lecture suivie d'une écriture
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := sqlstore.Migrate(db, Dialect, migrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return sqlstore.New(db, Dialect), nil
+}
+
+// isUniqueViolation indique si l'erreur provient d'une contrainte UNIQUE ou PRIMARY KEY
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
@@ -0,0 +1,92 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+)
+
+// CreateDailyChallenge enregistre le défi d'un jour
+func (s *Store) CreateDailyChallenge(challenge *game.DailyChallenge) error {
+	_, err := s.exec(`
+		INSERT INTO daily_challenges (day, language, word, hint, difficulty, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		challenge.Day, challenge.Language, challenge.Word, challenge.Hint, challenge.Difficulty,
+		challenge.CreatedAt.UnixNano(),
+	)
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return game.ErrDailyExists
+	}
+	return err
+}
+
+// GetDailyChallenge récupère le défi d'un jour
+func (s *Store) GetDailyChallenge(day, language string) (*game.DailyChallenge, error) {
+	var challenge game.DailyChallenge
+	var createdAt int64
+
+	err := s.queryRow(`
+		SELECT day, language, word, hint, difficulty, created_at
+		FROM daily_challenges WHERE language = ? AND day = ?`, language, day,
+	).Scan(&challenge.Day, &challenge.Language, &challenge.Word, &challenge.Hint, &challenge.Difficulty, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, game.ErrDailyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	challenge.CreatedAt = time.Unix(0, createdAt).UTC()
+	return &challenge, nil
+}
+
+// ListDailyWords retourne les mots des défis d'une langue entre deux jours, dans l'ordre des jours
+func (s *Store) ListDailyWords(language, from, to string) ([]string, error) {
+	rows, err := s.query(`
+		SELECT word FROM daily_challenges
+		WHERE language = ? AND day >= ? AND day < ? ORDER BY day`, language, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []string
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+// AddDailyPlay enregistre la partie d'un joueur pour un défi
+func (s *Store) AddDailyPlay(play game.DailyPlay) error {
+	_, err := s.exec(`
+		INSERT INTO daily_plays (day, language, player_id, game_id) VALUES (?, ?, ?, ?)`,
+		play.Day, play.Language, play.PlayerID, play.GameID,
+	)
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return game.ErrDailyAlreadyPlayed
+	}
+	return err
+}
+
+// GetDailyPlay récupère la partie d'un joueur pour un défi
+func (s *Store) GetDailyPlay(day, language, playerID string) (*game.DailyPlay, error) {
+	play := game.DailyPlay{Day: day, Language: language, PlayerID: playerID}
+	err := s.queryRow(`
+		SELECT game_id FROM daily_plays WHERE language = ? AND day = ? AND player_id = ?`,
+		language, day, playerID,
+	).Scan(&play.GameID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, game.ErrDailyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &play, nil
+}
@@ -0,0 +1,149 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+const matchColumns = `SELECT id, code, host_id, status, difficulty, language, category, matching, word, hint, seed, winner_id, created_at, starts_at, finished_at, version`
+
+// CreateMatch enregistre une nouvelle course et ses joueurs
+func (s *Store) CreateMatch(m *game.Match) error {
+	err := s.withTx(func(tx *sqlTx) error {
+		_, err := tx.exec(`
+			INSERT INTO matches (id, code, host_id, status, difficulty, language, category, matching, word, hint,
+				seed, winner_id, created_at, starts_at, finished_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			m.ID, m.Code, m.HostID, m.Status, m.Difficulty, m.Language, m.Category, m.Matching, m.Word, m.Hint,
+			m.Seed, m.WinnerID, m.CreatedAt.UnixNano(), unixNanoOrZero(m.StartsAt), unixNanoOrZero(m.FinishedAt),
+		)
+		if err != nil {
+			return err
+		}
+		return insertMatchPlayers(tx, m)
+	})
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return utils.ErrDuplicateID
+	}
+	return err
+}
+
+// GetMatch récupère une course par son ID
+func (s *Store) GetMatch(id string) (*game.Match, error) {
+	return s.loadMatch(`id = ?`, id)
+}
+
+// GetMatchByCode récupère une course par son code
+func (s *Store) GetMatchByCode(code string) (*game.Match, error) {
+	return s.loadMatch(`code = ?`, code)
+}
+
+// SaveMatch met à jour une course et remplace la liste de ses joueurs, si elle
+// n'a pas été enregistrée depuis sa lecture (game.ErrMatchConflict sinon)
+func (s *Store) SaveMatch(m *game.Match) error {
+	err := s.withTx(func(tx *sqlTx) error {
+		res, err := tx.exec(`
+			UPDATE matches SET status = ?, winner_id = ?, starts_at = ?, finished_at = ?, version = version + 1
+			WHERE id = ? AND version = ?`,
+			m.Status, m.WinnerID, unixNanoOrZero(m.StartsAt), unixNanoOrZero(m.FinishedAt), m.ID, m.Version,
+		)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			// Distinguer une course absente d'une course modifiée entre-temps
+			var exists int
+			if err := tx.queryRow(`SELECT COUNT(*) FROM matches WHERE id = ?`, m.ID).Scan(&exists); err != nil {
+				return err
+			}
+			if exists == 0 {
+				return game.ErrMatchNotFound
+			}
+			return game.ErrMatchConflict
+		}
+
+		if _, err := tx.exec(`DELETE FROM match_players WHERE match_id = ?`, m.ID); err != nil {
+			return err
+		}
+		return insertMatchPlayers(tx, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.Version++
+	return nil
+}
+
+// insertMatchPlayers enregistre les joueurs d'une course dans leur ordre d'arrivée
+func insertMatchPlayers(tx *sqlTx, m *game.Match) error {
+	for i, p := range m.Players {
+		_, err := tx.exec(`
+			INSERT INTO match_players (match_id, position, player_id, player_name, game_id, joined_at, final_rank)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			m.ID, i, p.PlayerID, p.PlayerName, p.GameID, p.JoinedAt.UnixNano(), p.Rank,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMatch lit la course désignée par la condition, puis ses joueurs, dans une
+// même transaction qui attend la fin d'un enregistrement en cours
+func (s *Store) loadMatch(condition string, arg any) (*game.Match, error) {
+	var m game.Match
+	err := s.withTx(func(tx *sqlTx) error {
+		var createdAt, startsAt, finishedAt int64
+		err := tx.queryRow(matchColumns+` FROM matches WHERE `+condition+s.dialect.ForShare, arg).Scan(
+			&m.ID, &m.Code, &m.HostID, &m.Status, &m.Difficulty, &m.Language, &m.Category, &m.Matching,
+			&m.Word, &m.Hint, &m.Seed, &m.WinnerID, &createdAt, &startsAt, &finishedAt, &m.Version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return game.ErrMatchNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		m.CreatedAt = time.Unix(0, createdAt).UTC()
+		if startsAt != 0 {
+			m.StartsAt = time.Unix(0, startsAt).UTC()
+		}
+		if finishedAt != 0 {
+			m.FinishedAt = time.Unix(0, finishedAt).UTC()
+		}
+
+		rows, err := tx.query(`
+			SELECT player_id, player_name, game_id, joined_at, final_rank
+			FROM match_players WHERE match_id = ? ORDER BY position`, m.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		m.Players = []game.MatchPlayer{}
+		for rows.Next() {
+			var p game.MatchPlayer
+			var joinedAt int64
+			if err := rows.Scan(&p.PlayerID, &p.PlayerName, &p.GameID, &joinedAt, &p.Rank); err != nil {
+				return err
+			}
+			p.JoinedAt = time.Unix(0, joinedAt).UTC()
+			m.Players = append(m.Players, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
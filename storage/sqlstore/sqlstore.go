@@ -0,0 +1,833 @@
+// Package sqlstore contient l'implémentation du stockage commune aux bases SQL.
+// Les paquets storage/sqlite et storage/postgres fournissent le driver, le
+// dialecte et les migrations propres à chaque moteur.
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Dialect décrit les différences de syntaxe entre les moteurs SQL
+type Dialect struct {
+	// NumberedPlaceholders remplace les ? par $1, $2... (PostgreSQL)
+	NumberedPlaceholders bool
+	// ForUpdate est ajouté aux SELECT qui précèdent une mise à jour dans une transaction
+	ForUpdate string
+	// ForShare est ajouté aux SELECT qui attendent la fin des mises à jour en
+	// cours pour lire plusieurs tables dans un état cohérent
+	ForShare string
+	// LockMigrations et UnlockMigrations sérialisent les migrations entre plusieurs serveurs
+	LockMigrations   string
+	UnlockMigrations string
+	// IsUniqueViolation indique si une erreur provient d'une contrainte UNIQUE ou PRIMARY KEY
+	IsUniqueViolation func(err error) bool
+}
+
+// rebind adapte les paramètres ? d'une requête au dialecte
+func (d Dialect) rebind(query string) string {
+	if !d.NumberedPlaceholders {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Store implémente game.GameStore, game.LeaderboardStore, game.SeasonStore,
+// game.WordStore, game.DailyStore, game.MatchStore et models.UserStore
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// New crée un Store sur une base déjà migrée
+func New(db *sql.DB, dialect Dialect) *Store {
+	return &Store{db: db, dialect: dialect}
+}
+
+// Close ferme la connexion à la base
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// exec exécute une requête en adaptant ses paramètres au dialecte
+func (s *Store) exec(query string, args ...any) (sql.Result, error) {
+	return s.db.Exec(s.dialect.rebind(query), args...)
+}
+
+// query exécute une requête de lecture en adaptant ses paramètres au dialecte
+func (s *Store) query(query string, args ...any) (*sql.Rows, error) {
+	return s.db.Query(s.dialect.rebind(query), args...)
+}
+
+// queryRow exécute une requête renvoyant une ligne en adaptant ses paramètres au dialecte
+func (s *Store) queryRow(query string, args ...any) *sql.Row {
+	return s.db.QueryRow(s.dialect.rebind(query), args...)
+}
+
+// withTx exécute fn dans une transaction validée uniquement si fn réussit
+func (s *Store) withTx(fn func(tx *sqlTx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&sqlTx{tx: tx, dialect: s.dialect}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sqlTx enveloppe une transaction en adaptant les paramètres au dialecte
+type sqlTx struct {
+	tx      *sql.Tx
+	dialect Dialect
+}
+
+func (t *sqlTx) exec(query string, args ...any) (sql.Result, error) {
+	return t.tx.Exec(t.dialect.rebind(query), args...)
+}
+
+func (t *sqlTx) queryRow(query string, args ...any) *sql.Row {
+	return t.tx.QueryRow(t.dialect.rebind(query), args...)
+}
+
+func (t *sqlTx) query(query string, args ...any) (*sql.Rows, error) {
+	return t.tx.Query(t.dialect.rebind(query), args...)
+}
+
+// CreateGame enregistre une nouvelle partie
+func (s *Store) CreateGame(g *game.Game) error {
+	guesses, err := marshalStrings(g.Guesses)
+	if err != nil {
+		return err
+	}
+	wordGuesses, err := marshalStrings(g.WordGuesses)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.exec(`
+		INSERT INTO games (id, word, guesses, word_guesses, remaining, status, score, difficulty, hint, share_code, player_id, matching, language, category, daily, seed, match_id, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		g.ID, g.Word, guesses, wordGuesses, g.Remaining, g.Status, g.Score, g.Difficulty, g.Hint,
+		nullString(g.ShareCode), nullString(g.PlayerID), g.Matching, g.Language, g.Category, g.Daily, g.Seed, g.Match, g.Version,
+	)
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return utils.ErrDuplicateID
+	}
+	return err
+}
+
+// SaveGame met à jour une partie existante (son code de partage ne change pas).
+// La condition sur la version empêche deux serveurs partageant la base
+// d'écraser mutuellement leurs propositions.
+func (s *Store) SaveGame(g *game.Game) error {
+	guesses, err := marshalStrings(g.Guesses)
+	if err != nil {
+		return err
+	}
+	wordGuesses, err := marshalStrings(g.WordGuesses)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.exec(`
+		UPDATE games SET word = ?, guesses = ?, word_guesses = ?, remaining = ?, status = ?, score = ?, difficulty = ?, hint = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`,
+		g.Word, guesses, wordGuesses, g.Remaining, g.Status, g.Score, g.Difficulty, g.Hint, g.ID, g.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// Distinguer une partie supprimée d'une partie modifiée entre-temps
+		var exists int
+		if err := s.queryRow(`SELECT COUNT(*) FROM games WHERE id = ?`, g.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return game.ErrGameNotFound
+		}
+		return game.ErrGameConflict
+	}
+
+	g.Version++
+	return nil
+}
+
+// GetGame récupère une partie par son ID
+func (s *Store) GetGame(id string) (*game.Game, error) {
+	return scanGame(s.queryRow(gameColumns+` FROM games WHERE id = ?`, id))
+}
+
+// GetGameByShareCode récupère une partie par son code de partage
+func (s *Store) GetGameByShareCode(code string) (*game.Game, error) {
+	return scanGame(s.queryRow(gameColumns+` FROM games WHERE share_code = ?`, code))
+}
+
+// DeleteGame supprime une partie
+func (s *Store) DeleteGame(id string) error {
+	res, err := s.exec(`DELETE FROM games WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return game.ErrGameNotFound
+	}
+
+	return nil
+}
+
+// WordStats compte les parties terminées et gagnées de chaque mot
+func (s *Store) WordStats() ([]game.WordStats, error) {
+	rows, err := s.query(`
+		SELECT language, word, COUNT(*), SUM(CASE WHEN status = 'won' THEN 1 ELSE 0 END)
+		FROM games WHERE status IN ('won', 'lost')
+		GROUP BY language, word ORDER BY language, word`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []game.WordStats{}
+	for rows.Next() {
+		var st game.WordStats
+		if err := rows.Scan(&st.Language, &st.Word, &st.Played, &st.Won); err != nil {
+			return nil, err
+		}
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
+
+// AddLeaderboardEntry ajoute un score au classement. Le joueur est verrouillé
+// dans la même transaction pour qu'une soumission ne croise pas sa suppression
+// ou la mise à jour concurrente de ses statistiques.
+func (s *Store) AddLeaderboardEntry(entry game.LeaderboardEntry) error {
+	return s.insertLeaderboardEntry(entry, nil)
+}
+
+// SubmitLeaderboardEntry ajoute un score au classement et met à jour les
+// statistiques du joueur dans la même transaction
+func (s *Store) SubmitLeaderboardEntry(entry game.LeaderboardEntry, won bool) error {
+	return s.insertLeaderboardEntry(entry, func(user *models.User) {
+		user.RecordGame(won, entry.Score, entry.SubmittedAt)
+	})
+}
+
+// insertLeaderboardEntry ajoute un score en verrouillant son joueur, à qui record
+// est appliqué avant l'enregistrement de ses statistiques s'il n'est pas nil
+func (s *Store) insertLeaderboardEntry(entry game.LeaderboardEntry, record func(*models.User)) error {
+	err := s.withTx(func(tx *sqlTx) error {
+		user, err := scanUser(tx.queryRow(userColumns+` FROM users WHERE id = ?`+s.dialect.ForUpdate, entry.PlayerID))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			INSERT INTO leaderboard_entries (id, game_id, player_id, player_name, score, word_length, remaining_attempts, wrong_guesses, difficulty, language, category, daily, submitted_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			entry.ID, nullString(entry.GameID), entry.PlayerID, entry.PlayerName, entry.Score, entry.WordLength,
+			entry.RemainingAttempts, entry.WrongGuesses, entry.Difficulty, entry.Language, entry.Category, entry.Daily, entry.SubmittedAt.UnixNano(),
+		)
+		if err != nil || record == nil {
+			return err
+		}
+
+		record(user)
+		_, err = tx.exec(`
+			UPDATE users SET games_played = ?, games_won = ?, high_score = ?, updated_at = ?
+			WHERE id = ?`,
+			user.GamesPlayed, user.GamesWon, user.HighScore, user.UpdatedAt.UnixNano(), user.ID,
+		)
+		return err
+	})
+	if err == nil || !s.dialect.IsUniqueViolation(err) {
+		return err
+	}
+
+	// Distinguer une partie déjà soumise d'une collision d'identifiant
+	var exists int
+	if lookupErr := s.queryRow(`SELECT COUNT(*) FROM leaderboard_entries WHERE game_id = ?`, entry.GameID).Scan(&exists); lookupErr != nil {
+		return lookupErr
+	}
+	if exists > 0 {
+		return game.ErrScoreAlreadySubmitted
+	}
+	return utils.ErrDuplicateID
+}
+
+// ListLeaderboardEntries retourne une page du classement. Le tri suit les index
+// leaderboard_entries_rank, leaderboard_entries_difficulty_rank,
+// leaderboard_entries_language_rank, leaderboard_entries_category_rank et
+// leaderboard_entries_daily_rank ; les périodes sont délimitées par l'index
+// leaderboard_entries_submitted_at.
+func (s *Store) ListLeaderboardEntries(query game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error) {
+	conditions, args := leaderboardFilter(query)
+	return s.rankedEntries("leaderboard_entries", conditions, args, query)
+}
+
+// PlayerStanding retourne la meilleure entrée d'un joueur et son rang, obtenu en
+// comptant les entrées classées avant elle
+func (s *Store) PlayerStanding(playerID string, query game.LeaderboardQuery) (*game.LeaderboardEntry, int, error) {
+	conditions, args := leaderboardFilter(query)
+	from, outer := rankedSource("leaderboard_entries", conditions, query.BestPerPlayer)
+
+	best, err := scanLeaderboardEntry(s.queryRow(leaderboardColumns+` FROM `+from+
+		whereClause(append(outer, "player_id = ?"))+` ORDER BY `+rankOrder+` LIMIT 1`,
+		append(args, playerID)...,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, game.ErrPlayerNotRanked
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var before int
+	err = s.queryRow(`SELECT COUNT(*) FROM `+from+whereClause(append(outer, rankedBefore)),
+		append(args, best.Score, best.Score, best.WrongGuesses, best.WrongGuesses,
+			best.SubmittedAt.UnixNano(), best.SubmittedAt.UnixNano(), best.ID)...,
+	).Scan(&before)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return best, before + 1, nil
+}
+
+// rankOrder est l'ordre du classement, identique à LeaderboardEntry.RanksBefore
+const rankOrder = `score DESC, wrong_guesses, submitted_at, id`
+
+// rankedBefore sélectionne les entrées classées avant une entrée donnée
+// (paramètres : score, score, erreurs, erreurs, date, date, ID)
+const rankedBefore = `(score > ? OR (score = ? AND (wrong_guesses < ? OR (wrong_guesses = ? AND
+	(submitted_at < ? OR (submitted_at = ? AND id < ?))))))`
+
+// rankedEntries compte puis lit une page des scores de table vérifiant conditions,
+// dans l'ordre du classement
+func (s *Store) rankedEntries(table string, conditions []string, args []any, query game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error) {
+	from, outer := rankedSource(table, conditions, query.BestPerPlayer)
+	where := whereClause(outer)
+
+	var total int
+	if err := s.queryRow(`SELECT COUNT(*) FROM `+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.query(leaderboardColumns+` FROM `+from+where+`
+		ORDER BY `+rankOrder+`
+		LIMIT ? OFFSET ?`,
+		append(args, query.Limit, query.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []game.LeaderboardEntry{}
+	for rows.Next() {
+		entry, err := scanLeaderboardEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// rankedSource retourne la source et les conditions qui sélectionnent les scores
+// de table vérifiant conditions. Si best est vrai, seule la meilleure entrée de
+// chaque joueur est gardée. Les paramètres des conditions restent dans le même ordre.
+func rankedSource(table string, conditions []string, best bool) (string, []string) {
+	if !best {
+		return table, conditions
+	}
+
+	from := `(SELECT *, ROW_NUMBER() OVER (PARTITION BY player_id ORDER BY ` + rankOrder + `) AS player_rank
+		FROM ` + table + whereClause(conditions) + `) AS ranked`
+	return from, []string{"player_rank = 1"}
+}
+
+// whereClause assemble des conditions en clause WHERE (vide s'il n'y en a pas)
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// leaderboardFilter construit les conditions correspondant aux filtres de la requête
+func leaderboardFilter(query game.LeaderboardQuery) ([]string, []any) {
+	var conditions []string
+	var args []any
+
+	if query.Difficulty != "" {
+		conditions = append(conditions, "difficulty = ?")
+		args = append(args, query.Difficulty)
+	}
+	if query.Language != "" {
+		conditions = append(conditions, "language = ?")
+		args = append(args, query.Language)
+	}
+	if query.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, query.Category)
+	}
+	if query.Daily != "" {
+		conditions = append(conditions, "daily = ?")
+		args = append(args, query.Daily)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, "submitted_at >= ?")
+		args = append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "submitted_at < ?")
+		args = append(args, query.To.UnixNano())
+	}
+
+	return conditions, args
+}
+
+// CreateSeason enregistre une nouvelle saison
+func (s *Store) CreateSeason(season *game.Season) error {
+	_, err := s.exec(`
+		INSERT INTO seasons (id, name, starts_at, ends_at, archived_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		season.ID, season.Name, season.StartsAt.UnixNano(), season.EndsAt.UnixNano(), unixNanoOrZero(season.ArchivedAt),
+	)
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		// Distinguer une collision d'identifiant d'un nom déjà utilisé
+		var exists int
+		if lookupErr := s.queryRow(`SELECT COUNT(*) FROM seasons WHERE id = ?`, season.ID).Scan(&exists); lookupErr != nil {
+			return lookupErr
+		}
+		if exists > 0 {
+			return utils.ErrDuplicateID
+		}
+		return game.ErrSeasonNameTaken
+	}
+	return err
+}
+
+// GetSeason récupère une saison par son ID
+func (s *Store) GetSeason(id string) (*game.Season, error) {
+	return scanSeason(s.queryRow(seasonColumns+` FROM seasons WHERE id = ?`, id))
+}
+
+// ListSeasons retourne toutes les saisons, les plus récentes en premier
+func (s *Store) ListSeasons() ([]game.Season, error) {
+	rows, err := s.query(seasonColumns + ` FROM seasons ORDER BY starts_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := []game.Season{}
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, *season)
+	}
+
+	return seasons, rows.Err()
+}
+
+// ArchiveSeason copie les scores de la saison dans season_entries. La saison
+// est marquée archivée dans la même transaction, ce qui garantit une seule
+// copie même si plusieurs serveurs archivent en même temps.
+func (s *Store) ArchiveSeason(id string, archivedAt time.Time) error {
+	return s.withTx(func(tx *sqlTx) error {
+		season, err := scanSeason(tx.queryRow(seasonColumns+` FROM seasons WHERE id = ?`+s.dialect.ForUpdate, id))
+		if err != nil {
+			return err
+		}
+		if !season.ArchivedAt.IsZero() {
+			return game.ErrSeasonArchived
+		}
+
+		if _, err := tx.exec(`UPDATE seasons SET archived_at = ? WHERE id = ?`, archivedAt.UnixNano(), id); err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			INSERT INTO season_entries (season_id, id, game_id, player_id, player_name, score, word_length,
+				remaining_attempts, wrong_guesses, difficulty, language, category, daily, submitted_at)
+			SELECT ?, id, game_id, player_id, player_name, score, word_length,
+				remaining_attempts, wrong_guesses, difficulty, language, category, daily, submitted_at
+			FROM leaderboard_entries WHERE submitted_at >= ? AND submitted_at < ?`,
+			id, season.StartsAt.UnixNano(), season.EndsAt.UnixNano(),
+		)
+		return err
+	})
+}
+
+// ListSeasonEntries retourne une page du classement archivé d'une saison
+func (s *Store) ListSeasonEntries(seasonID string, query game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error) {
+	query.From, query.To = time.Time{}, time.Time{}
+	conditions, args := leaderboardFilter(query)
+	conditions = append([]string{"season_id = ?"}, conditions...)
+	args = append([]any{seasonID}, args...)
+	return s.rankedEntries("season_entries", conditions, args, query)
+}
+
+// CreateUser enregistre un nouvel utilisateur
+func (s *Store) CreateUser(user *models.User) error {
+	roles, err := marshalStrings(user.Roles)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.exec(`
+		INSERT INTO users (id, name, email, password, games_played, games_won, high_score, roles, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Name, user.Email, user.Password, user.GamesPlayed, user.GamesWon, user.HighScore,
+		roles, user.CreatedAt.UnixNano(), user.UpdatedAt.UnixNano(),
+	)
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		// La contrainte violée est soit la clé primaire, soit le nom
+		var exists int
+		if lookupErr := s.queryRow(`SELECT COUNT(*) FROM users WHERE id = ?`, user.ID).Scan(&exists); lookupErr != nil {
+			return lookupErr
+		}
+		if exists > 0 {
+			return utils.ErrDuplicateID
+		}
+		return models.ErrUsernameTaken
+	}
+	return err
+}
+
+// GetUser récupère un utilisateur par son ID
+func (s *Store) GetUser(id string) (*models.User, error) {
+	return scanUser(s.queryRow(userColumns+` FROM users WHERE id = ?`, id))
+}
+
+// GetUserByName récupère un utilisateur par son nom
+func (s *Store) GetUserByName(username string) (*models.User, error) {
+	return scanUser(s.queryRow(userColumns+` FROM users WHERE name = ?`, username))
+}
+
+// UpdateUser applique fn à l'utilisateur puis l'enregistre dans une transaction.
+// La ligne est verrouillée pendant fn pour que deux mises à jour concurrentes
+// (par exemple deux changements de mot de passe) ne s'écrasent pas.
+func (s *Store) UpdateUser(id string, fn func(*models.User) error) (*models.User, error) {
+	var user *models.User
+	err := s.withTx(func(tx *sqlTx) error {
+		var err error
+		user, err = scanUser(tx.queryRow(userColumns+` FROM users WHERE id = ?`+s.dialect.ForUpdate, id))
+		if err != nil {
+			return err
+		}
+
+		if err := fn(user); err != nil {
+			return err
+		}
+
+		roles, err := marshalStrings(user.Roles)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			UPDATE users SET email = ?, password = ?, games_played = ?, games_won = ?, high_score = ?, roles = ?, updated_at = ?
+			WHERE id = ?`,
+			user.Email, user.Password, user.GamesPlayed, user.GamesWon, user.HighScore, roles, user.UpdatedAt.UnixNano(), user.ID,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// CreateSession enregistre une nouvelle session
+func (s *Store) CreateSession(session *models.Session) error {
+	_, err := s.exec(`
+		INSERT INTO sessions (id, user_id, access_token_hash, refresh_token_hash,
+			access_expires_at, refresh_expires_at, created_at, refreshed_at, user_agent)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ID, session.UserID, session.AccessTokenHash, session.RefreshTokenHash,
+		session.AccessExpiresAt.UnixNano(), session.RefreshExpiresAt.UnixNano(),
+		session.CreatedAt.UnixNano(), session.RefreshedAt.UnixNano(), session.UserAgent,
+	)
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return utils.ErrDuplicateID
+	}
+	return err
+}
+
+// GetSessionByAccessToken récupère une session par l'empreinte de son token d'accès
+func (s *Store) GetSessionByAccessToken(tokenHash string) (*models.Session, error) {
+	return scanSession(s.queryRow(sessionColumns+` FROM sessions WHERE access_token_hash = ?`, tokenHash))
+}
+
+// GetSessionByRefreshToken récupère une session par l'empreinte de son token de rafraîchissement
+func (s *Store) GetSessionByRefreshToken(tokenHash string) (*models.Session, error) {
+	return scanSession(s.queryRow(sessionColumns+` FROM sessions WHERE refresh_token_hash = ?`, tokenHash))
+}
+
+// RotateSession remplace les tokens d'une session si le token de rafraîchissement n'a pas changé
+func (s *Store) RotateSession(session *models.Session, oldRefreshHash string) error {
+	res, err := s.exec(`
+		UPDATE sessions SET access_token_hash = ?, refresh_token_hash = ?,
+			access_expires_at = ?, refresh_expires_at = ?, refreshed_at = ?
+		WHERE id = ? AND refresh_token_hash = ?`,
+		session.AccessTokenHash, session.RefreshTokenHash,
+		session.AccessExpiresAt.UnixNano(), session.RefreshExpiresAt.UnixNano(), session.RefreshedAt.UnixNano(),
+		session.ID, oldRefreshHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Aucune ligne modifiée : le token a déjà été utilisé par une autre requête
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrInvalidToken
+	}
+	return nil
+}
+
+// ListUserSessions retourne les sessions d'un utilisateur, les plus récentes en premier
+func (s *Store) ListUserSessions(userID string) ([]models.Session, error) {
+	rows, err := s.query(sessionColumns+` FROM sessions WHERE user_id = ? ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+// DeleteSession supprime une session
+func (s *Store) DeleteSession(id string) error {
+	res, err := s.exec(`DELETE FROM sessions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteUserSessions supprime toutes les sessions d'un utilisateur
+func (s *Store) DeleteUserSessions(userID string) error {
+	_, err := s.exec(`DELETE FROM sessions WHERE user_id = ?`, userID)
+	return err
+}
+
+// DeleteExpiredSessions supprime les sessions dont le token de rafraîchissement a expiré
+func (s *Store) DeleteExpiredSessions(now time.Time) (int, error) {
+	res, err := s.exec(`DELETE FROM sessions WHERE refresh_expires_at <= ?`, now.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+const gameColumns = `SELECT id, word, guesses, word_guesses, remaining, status, score, difficulty, hint, share_code, player_id, matching, language, category, daily, seed, match_id, version`
+
+// scanGame lit une partie depuis une ligne sélectionnée avec gameColumns
+func scanGame(row rowScanner) (*game.Game, error) {
+	var g game.Game
+	var guesses, wordGuesses string
+	var shareCode, playerID sql.NullString
+
+	err := row.Scan(&g.ID, &g.Word, &guesses, &wordGuesses, &g.Remaining, &g.Status, &g.Score, &g.Difficulty, &g.Hint, &shareCode, &playerID, &g.Matching, &g.Language, &g.Category, &g.Daily, &g.Seed, &g.Match, &g.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, game.ErrGameNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(guesses), &g.Guesses); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(wordGuesses), &g.WordGuesses); err != nil {
+		return nil, err
+	}
+
+	g.ShareCode = shareCode.String
+	g.PlayerID = playerID.String
+	return &g, nil
+}
+
+const leaderboardColumns = `SELECT id, game_id, player_id, player_name, score, word_length, remaining_attempts,
+	wrong_guesses, difficulty, language, category, daily, submitted_at`
+
+// scanLeaderboardEntry lit un score depuis une ligne sélectionnée avec leaderboardColumns
+func scanLeaderboardEntry(row rowScanner) (*game.LeaderboardEntry, error) {
+	var entry game.LeaderboardEntry
+	var gameID sql.NullString
+	var submittedAt int64
+
+	err := row.Scan(&entry.ID, &gameID, &entry.PlayerID, &entry.PlayerName, &entry.Score, &entry.WordLength,
+		&entry.RemainingAttempts, &entry.WrongGuesses, &entry.Difficulty, &entry.Language, &entry.Category, &entry.Daily, &submittedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.GameID = gameID.String
+	entry.SubmittedAt = time.Unix(0, submittedAt).UTC()
+	return &entry, nil
+}
+
+const seasonColumns = `SELECT id, name, starts_at, ends_at, archived_at`
+
+// scanSeason lit une saison depuis une ligne sélectionnée avec seasonColumns
+func scanSeason(row rowScanner) (*game.Season, error) {
+	var season game.Season
+	var startsAt, endsAt, archivedAt int64
+
+	err := row.Scan(&season.ID, &season.Name, &startsAt, &endsAt, &archivedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, game.ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	season.StartsAt = time.Unix(0, startsAt).UTC()
+	season.EndsAt = time.Unix(0, endsAt).UTC()
+	if archivedAt != 0 {
+		season.ArchivedAt = time.Unix(0, archivedAt).UTC()
+	}
+	return &season, nil
+}
+
+// unixNanoOrZero convertit une date en nanosecondes Unix, 0 pour une date nulle
+func unixNanoOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+const userColumns = `SELECT id, name, email, password, games_played, games_won, high_score, roles, created_at, updated_at`
+
+// marshalStrings encode une liste de chaînes en tableau JSON (jamais "null")
+func marshalStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+// nullString convertit une chaîne vide en NULL (pour les colonnes UNIQUE facultatives)
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// rowScanner est implémenté par *sql.Row et *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanUser lit un utilisateur depuis une ligne sélectionnée avec userColumns
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var roles string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password,
+		&user.GamesPlayed, &user.GamesWon, &user.HighScore, &roles, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(roles), &user.Roles); err != nil {
+		return nil, err
+	}
+
+	user.CreatedAt = time.Unix(0, createdAt)
+	user.UpdatedAt = time.Unix(0, updatedAt)
+	return &user, nil
+}
+
+const sessionColumns = `SELECT id, user_id, access_token_hash, refresh_token_hash,
+	access_expires_at, refresh_expires_at, created_at, refreshed_at, user_agent`
+
+// scanSession lit une session depuis une ligne sélectionnée avec sessionColumns
+func scanSession(row rowScanner) (*models.Session, error) {
+	var session models.Session
+	var accessExpiresAt, refreshExpiresAt, createdAt, refreshedAt int64
+
+	err := row.Scan(&session.ID, &session.UserID, &session.AccessTokenHash, &session.RefreshTokenHash,
+		&accessExpiresAt, &refreshExpiresAt, &createdAt, &refreshedAt, &session.UserAgent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.AccessExpiresAt = time.Unix(0, accessExpiresAt)
+	session.RefreshExpiresAt = time.Unix(0, refreshExpiresAt)
+	session.CreatedAt = time.Unix(0, createdAt)
+	session.RefreshedAt = time.Unix(0, refreshedAt)
+	return &session, nil
+}
@@ -0,0 +1,293 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// CreateWord enregistre un nouveau mot et l'entrée de journal de sa création
+func (s *Store) CreateWord(word *game.WordEntry, audit game.WordAudit) error {
+	return s.SaveWords([]game.WordChange{{Word: word, Audit: audit, New: true}})
+}
+
+// SaveWords enregistre un lot de créations et de modifications de mots, avec
+// leurs entrées de journal, dans une seule transaction. L'unicité des mots est
+// vérifiée avant chaque écriture : une violation de contrainte ne peut alors
+// venir que d'un ID (ou d'un mot inséré en même temps, que le nouvel essai de
+// l'appelant détectera).
+func (s *Store) SaveWords(changes []game.WordChange) error {
+	err := s.withTx(func(tx *sqlTx) error {
+		for _, change := range changes {
+			if err := saveWord(tx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return utils.ErrDuplicateID
+	}
+	return err
+}
+
+// GetWord récupère un mot par son ID
+func (s *Store) GetWord(id string) (*game.WordEntry, error) {
+	return scanWord(s.queryRow(wordColumns+` FROM words WHERE id = ?`, id))
+}
+
+// FindWord récupère un mot par sa langue et sa clé de comparaison
+func (s *Store) FindWord(language, key string) (*game.WordEntry, error) {
+	return scanWord(s.queryRow(wordColumns+` FROM words WHERE language = ? AND word_key = ?`, language, key))
+}
+
+// ListWords retourne les mots correspondant au filtre
+func (s *Store) ListWords(filter game.WordFilter) ([]game.WordEntry, error) {
+	var conditions []string
+	var args []any
+
+	if filter.Language != "" {
+		conditions = append(conditions, "language = ?")
+		args = append(args, filter.Language)
+	}
+	if filter.Difficulty != "" {
+		conditions = append(conditions, "difficulty = ?")
+		args = append(args, filter.Difficulty)
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, filter.Category)
+	}
+	if filter.Enabled != nil {
+		conditions = append(conditions, "enabled = ?")
+		args = append(args, *filter.Enabled)
+	}
+	if search := game.WordKey(filter.Search); search != "" {
+		conditions = append(conditions, `word_key LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+likeEscaper.Replace(search)+"%")
+	}
+
+	rows, err := s.query(wordColumns+` FROM words`+whereClause(conditions)+` ORDER BY language, word`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := []game.WordEntry{}
+	for rows.Next() {
+		word, err := scanWord(rows)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, *word)
+	}
+
+	return words, rows.Err()
+}
+
+// UpdateWord remplace un mot existant et enregistre l'entrée de journal
+func (s *Store) UpdateWord(word *game.WordEntry, audit game.WordAudit) error {
+	return s.SaveWords([]game.WordChange{{Word: word, Audit: audit}})
+}
+
+// DeleteWord supprime un mot et enregistre l'entrée de journal
+func (s *Store) DeleteWord(id string, audit game.WordAudit) error {
+	err := s.withTx(func(tx *sqlTx) error {
+		res, err := tx.exec(`DELETE FROM words WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			if err == nil {
+				err = game.ErrWordNotFound
+			}
+			return err
+		}
+
+		return insertWordAudit(tx, audit)
+	})
+	if err != nil && s.dialect.IsUniqueViolation(err) {
+		return utils.ErrDuplicateID
+	}
+	return err
+}
+
+// ListWordAudit retourne les dernières entrées du journal, les plus récentes en premier
+func (s *Store) ListWordAudit(wordID string, limit int) ([]game.WordAudit, error) {
+	var conditions []string
+	var args []any
+	if wordID != "" {
+		conditions = append(conditions, "word_id = ?")
+		args = append(args, wordID)
+	}
+
+	rows, err := s.query(`SELECT id, word_id, action, user_id, user_name, before_state, after_state, created_at
+		FROM word_audit`+whereClause(conditions)+` ORDER BY created_at DESC, id DESC LIMIT ?`,
+		append(args, limit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []game.WordAudit{}
+	for rows.Next() {
+		var audit game.WordAudit
+		var userID, before, after sql.NullString
+		var createdAt int64
+		err := rows.Scan(&audit.ID, &audit.WordID, &audit.Action, &userID, &audit.UserName, &before, &after, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		audit.UserID = userID.String
+		if audit.Before, err = unmarshalWord(before); err != nil {
+			return nil, err
+		}
+		if audit.After, err = unmarshalWord(after); err != nil {
+			return nil, err
+		}
+		audit.At = time.Unix(0, createdAt).UTC()
+		entries = append(entries, audit)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountWordAudit retourne le nombre d'entrées du journal des mots
+func (s *Store) CountWordAudit() (int, error) {
+	var count int
+	err := s.queryRow(`SELECT COUNT(*) FROM word_audit`).Scan(&count)
+	return count, err
+}
+
+// wordExists renvoie game.ErrWordExists si un autre mot de la même langue a la même clé
+func wordExists(tx *sqlTx, word *game.WordEntry) error {
+	var count int
+	err := tx.queryRow(`SELECT COUNT(*) FROM words WHERE language = ? AND word_key = ? AND id <> ?`,
+		word.Language, game.WordKey(word.Word), word.ID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return game.ErrWordExists
+	}
+	return nil
+}
+
+// saveWord insère ou remplace un mot et enregistre son entrée de journal
+func saveWord(tx *sqlTx, change game.WordChange) error {
+	word := change.Word
+	tags, err := marshalStrings(word.Tags)
+	if err != nil {
+		return err
+	}
+	if err := wordExists(tx, word); err != nil {
+		return err
+	}
+
+	if change.New {
+		_, err = tx.exec(`
+			INSERT INTO words (id, word, word_key, hint, difficulty, category, language, tags, enabled, from_files, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			word.ID, word.Word, game.WordKey(word.Word), word.Hint, word.Difficulty, word.Category, word.Language, tags,
+			word.Enabled, word.FromFiles, word.CreatedAt.UnixNano(), word.UpdatedAt.UnixNano(),
+		)
+		if err != nil {
+			return err
+		}
+		return insertWordAudit(tx, change.Audit)
+	}
+
+	res, err := tx.exec(`
+		UPDATE words SET word = ?, word_key = ?, hint = ?, difficulty = ?, category = ?, language = ?, tags = ?, enabled = ?, from_files = ?, updated_at = ?
+		WHERE id = ?`,
+		word.Word, game.WordKey(word.Word), word.Hint, word.Difficulty, word.Category, word.Language, tags, word.Enabled,
+		word.FromFiles, word.UpdatedAt.UnixNano(), word.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = game.ErrWordNotFound
+		}
+		return err
+	}
+
+	return insertWordAudit(tx, change.Audit)
+}
+
+// insertWordAudit enregistre une entrée du journal des mots
+func insertWordAudit(tx *sqlTx, audit game.WordAudit) error {
+	before, err := marshalWord(audit.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalWord(audit.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`
+		INSERT INTO word_audit (id, word_id, action, user_id, user_name, before_state, after_state, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		audit.ID, audit.WordID, audit.Action, nullString(audit.UserID), audit.UserName, before, after, audit.At.UnixNano(),
+	)
+	return err
+}
+
+// marshalWord encode l'état d'un mot pour le journal (NULL si absent)
+func marshalWord(word *game.WordEntry) (sql.NullString, error) {
+	if word == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(word)
+	return nullString(string(data)), err
+}
+
+// unmarshalWord décode l'état d'un mot enregistré par marshalWord
+func unmarshalWord(data sql.NullString) (*game.WordEntry, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+	var word game.WordEntry
+	if err := json.Unmarshal([]byte(data.String), &word); err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// likeEscaper protège les caractères spéciaux de LIKE (avec ESCAPE '\')
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+const wordColumns = `SELECT id, word, hint, difficulty, category, language, tags, enabled, from_files, created_at, updated_at`
+
+// scanWord lit un mot depuis une ligne sélectionnée avec wordColumns
+func scanWord(row rowScanner) (*game.WordEntry, error) {
+	var word game.WordEntry
+	var tags string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(&word.ID, &word.Word, &word.Hint, &word.Difficulty, &word.Category, &word.Language, &tags,
+		&word.Enabled, &word.FromFiles, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, game.ErrWordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &word.Tags); err != nil {
+		return nil, err
+	}
+
+	word.CreatedAt = time.Unix(0, createdAt).UTC()
+	word.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return &word, nil
+}
@@ -0,0 +1,673 @@
+// Package storetest contient la suite de tests commune aux implémentations du
+// stockage : chaque implémentation l'exécute sur une base vide.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/N95Ryan/8bit-hangman-back/game"
+	"github.com/N95Ryan/8bit-hangman-back/models"
+	"github.com/N95Ryan/8bit-hangman-back/utils"
+)
+
+// Store regroupe les interfaces de stockage vérifiées par la suite
+type Store interface {
+	game.DailyStore
+	game.GameStore
+	game.LeaderboardStore
+	game.MatchStore
+	game.SeasonStore
+	game.WordStore
+	models.UserStore
+}
+
+// Run exécute la suite. open est appelé par chaque sous-test et doit retourner
+// un stockage vide, fermé par ses propres t.Cleanup.
+func Run(t *testing.T, open func(t *testing.T) Store) {
+	t.Run("RankTies", func(t *testing.T) { testRankTies(t, open(t)) })
+	t.Run("PeriodBounds", func(t *testing.T) { testPeriodBounds(t, open(t)) })
+	t.Run("CategoryFilter", func(t *testing.T) { testCategoryFilter(t, open(t)) })
+	t.Run("SeasonArchive", func(t *testing.T) { testSeasonArchive(t, open(t)) })
+	t.Run("RefreshRotation", func(t *testing.T) { testRefreshRotation(t, open(t)) })
+	t.Run("DuplicateIDRetry", func(t *testing.T) { testDuplicateIDRetry(t, open(t)) })
+	t.Run("ScoreSubmission", func(t *testing.T) { testScoreSubmission(t, open(t)) })
+	t.Run("GameVersions", func(t *testing.T) { testGameVersions(t, open(t)) })
+	t.Run("MatchVersions", func(t *testing.T) { testMatchVersions(t, open(t)) })
+	t.Run("DailyPlays", func(t *testing.T) { testDailyPlays(t, open(t)) })
+	t.Run("WordBatch", func(t *testing.T) { testWordBatch(t, open(t)) })
+}
+
+// testRankTies vérifie que les égalités de score sont départagées par le nombre
+// d'erreurs, puis la date de soumission, puis l'ID, dans les pages comme dans les rangs
+func testRankTies(t *testing.T, store Store) {
+	alice := createUser(t, store, "alice")
+	bob := createUser(t, store, "bob")
+	carol := createUser(t, store, "carol")
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []game.LeaderboardEntry{
+		{ID: "E1", GameID: "G1", PlayerID: alice.ID, Score: 100, WrongGuesses: 2, SubmittedAt: at},
+		{ID: "E2", GameID: "G2", PlayerID: bob.ID, Score: 100, WrongGuesses: 1, SubmittedAt: at.Add(time.Second)},
+		{ID: "E3", GameID: "G3", PlayerID: carol.ID, Score: 100, WrongGuesses: 1, SubmittedAt: at.Add(time.Second)},
+		{ID: "E0", GameID: "G0", PlayerID: carol.ID, Score: 100, WrongGuesses: 1, SubmittedAt: at},
+		{ID: "E4", GameID: "G4", PlayerID: alice.ID, Score: 150, WrongGuesses: 3, SubmittedAt: at.Add(time.Hour)},
+	}
+	for _, entry := range entries {
+		entry.PlayerName = "player"
+		entry.Difficulty = "medium"
+		entry.Language = "en"
+		if err := store.AddLeaderboardEntry(entry); err != nil {
+			t.Fatalf("AddLeaderboardEntry(%s): %v", entry.ID, err)
+		}
+	}
+
+	checkPage(t, store, game.LeaderboardQuery{Limit: 10}, []string{"E4", "E0", "E2", "E3", "E1"}, 5)
+	checkPage(t, store, game.LeaderboardQuery{Offset: 1, Limit: 2}, []string{"E0", "E2"}, 5)
+	checkPage(t, store, game.LeaderboardQuery{BestPerPlayer: true, Limit: 10}, []string{"E4", "E0", "E2"}, 3)
+
+	checkStanding(t, store, bob.ID, game.LeaderboardQuery{}, "E2", 3)
+	checkStanding(t, store, carol.ID, game.LeaderboardQuery{}, "E0", 2)
+	checkStanding(t, store, alice.ID, game.LeaderboardQuery{}, "E4", 1)
+	checkStanding(t, store, bob.ID, game.LeaderboardQuery{BestPerPlayer: true}, "E2", 3)
+}
+
+// testPeriodBounds vérifie qu'une période compte les scores soumis à partir de
+// son début et jusqu'à sa fin exclue, combinée aux autres filtres
+func testPeriodBounds(t *testing.T, store Store) {
+	alice := createUser(t, store, "alice")
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	entries := []game.LeaderboardEntry{
+		{ID: "E1", Score: 500, SubmittedAt: from.Add(-time.Millisecond)},
+		{ID: "E2", Score: 100, SubmittedAt: from},
+		{ID: "E3", Score: 300, SubmittedAt: from.Add(72 * time.Hour), Difficulty: "hard"},
+		{ID: "E4", Score: 200, SubmittedAt: to.Add(-time.Millisecond)},
+		{ID: "E5", Score: 400, SubmittedAt: to},
+	}
+	for _, entry := range entries {
+		entry.GameID = "G" + entry.ID
+		entry.PlayerID = alice.ID
+		entry.PlayerName = alice.Name
+		if entry.Difficulty == "" {
+			entry.Difficulty = "medium"
+		}
+		entry.Language = "en"
+		if err := store.AddLeaderboardEntry(entry); err != nil {
+			t.Fatalf("AddLeaderboardEntry(%s): %v", entry.ID, err)
+		}
+	}
+
+	checkPage(t, store, game.LeaderboardQuery{From: from, To: to, Limit: 10}, []string{"E3", "E4", "E2"}, 3)
+	checkPage(t, store, game.LeaderboardQuery{From: from, Limit: 10}, []string{"E5", "E3", "E4", "E2"}, 4)
+	checkPage(t, store, game.LeaderboardQuery{To: to, Limit: 10}, []string{"E1", "E3", "E4", "E2"}, 4)
+	checkPage(t, store, game.LeaderboardQuery{From: from, To: to, Difficulty: "medium", Offset: 1, Limit: 10}, []string{"E2"}, 2)
+	checkStanding(t, store, alice.ID, game.LeaderboardQuery{From: from, To: to}, "E3", 1)
+}
+
+// testCategoryFilter vérifie qu'un classement par catégorie ne garde que ses
+// parties, tandis que le classement sans catégorie les garde toutes
+func testCategoryFilter(t *testing.T, store Store) {
+	alice := createUser(t, store, "alice")
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []game.LeaderboardEntry{
+		{ID: "E1", Score: 400, Language: "en", Category: "technology"},
+		{ID: "E2", Score: 300, Language: "fr", Category: "technology"},
+		{ID: "E3", Score: 200, Language: "en"},
+		{ID: "E4", Score: 100, Language: "en", Category: "characters"},
+	}
+	for i, entry := range entries {
+		entry.GameID = "G" + entry.ID
+		entry.PlayerID = alice.ID
+		entry.PlayerName = alice.Name
+		entry.Difficulty = "easy"
+		entry.SubmittedAt = at.Add(time.Duration(i) * time.Second)
+		if err := store.AddLeaderboardEntry(entry); err != nil {
+			t.Fatalf("AddLeaderboardEntry(%s): %v", entry.ID, err)
+		}
+	}
+
+	checkPage(t, store, game.LeaderboardQuery{Limit: 10}, []string{"E1", "E2", "E3", "E4"}, 4)
+	checkPage(t, store, game.LeaderboardQuery{Category: "technology", Limit: 10}, []string{"E1", "E2"}, 2)
+	checkPage(t, store, game.LeaderboardQuery{Category: "technology", Language: "fr", Limit: 10}, []string{"E2"}, 1)
+	checkPage(t, store, game.LeaderboardQuery{Category: "characters", Difficulty: "easy", Limit: 10}, []string{"E4"}, 1)
+	checkPage(t, store, game.LeaderboardQuery{Category: "sports", Limit: 10}, nil, 0)
+	checkStanding(t, store, alice.ID, game.LeaderboardQuery{Category: "characters"}, "E4", 1)
+}
+
+// testSeasonArchive vérifie que l'archivage fige le classement d'une saison :
+// seuls les scores de sa période y figurent, et ceux ajoutés ensuite n'y entrent pas
+func testSeasonArchive(t *testing.T, store Store) {
+	alice := createUser(t, store, "alice")
+	bob := createUser(t, store, "bob")
+
+	startsAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	season, err := game.CreateSeason(store, "Spring", startsAt, startsAt.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("CreateSeason: %v", err)
+	}
+	if _, err := game.CreateSeason(store, "Spring", startsAt, startsAt.AddDate(0, 2, 0)); !errors.Is(err, game.ErrSeasonNameTaken) {
+		t.Fatalf("CreateSeason(same name) = %v, want %v", err, game.ErrSeasonNameTaken)
+	}
+
+	add := func(id string, player *models.User, score int, at time.Time) {
+		t.Helper()
+		entry := game.LeaderboardEntry{ID: id, GameID: "G" + id, PlayerID: player.ID, PlayerName: player.Name,
+			Score: score, Difficulty: "medium", Language: "en", SubmittedAt: at}
+		if err := store.AddLeaderboardEntry(entry); err != nil {
+			t.Fatalf("AddLeaderboardEntry(%s): %v", id, err)
+		}
+	}
+	add("E1", alice, 900, startsAt.Add(-time.Hour))
+	add("E2", alice, 100, startsAt)
+	add("E3", bob, 300, startsAt.AddDate(0, 0, 10))
+	add("E4", alice, 200, startsAt.AddDate(0, 0, 20))
+	add("E5", bob, 800, season.EndsAt)
+
+	if err := store.ArchiveSeason(season.ID, season.EndsAt); err != nil {
+		t.Fatalf("ArchiveSeason: %v", err)
+	}
+	if err := store.ArchiveSeason(season.ID, season.EndsAt); !errors.Is(err, game.ErrSeasonArchived) {
+		t.Errorf("ArchiveSeason(archived season) = %v, want %v", err, game.ErrSeasonArchived)
+	}
+	if err := store.ArchiveSeason("missing", season.EndsAt); !errors.Is(err, game.ErrSeasonNotFound) {
+		t.Errorf("ArchiveSeason(unknown season) = %v, want %v", err, game.ErrSeasonNotFound)
+	}
+
+	// Un score tardif daté de la saison n'entre pas dans son classement archivé
+	add("E6", bob, 700, startsAt.AddDate(0, 0, 25))
+
+	archived, err := store.GetSeason(season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason: %v", err)
+	}
+	if archived.Status(time.Now()) != game.SeasonArchived {
+		t.Errorf("status after archiving = %s, want %s", archived.Status(time.Now()), game.SeasonArchived)
+	}
+	list := func(query game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error) {
+		return store.ListSeasonEntries(season.ID, query)
+	}
+	checkEntries(t, list, game.LeaderboardQuery{Limit: 10}, []string{"E3", "E4", "E2"}, 3)
+	checkEntries(t, list, game.LeaderboardQuery{Offset: 1, Limit: 1}, []string{"E4"}, 3)
+	checkEntries(t, list, game.LeaderboardQuery{BestPerPlayer: true, Limit: 10}, []string{"E3", "E4"}, 2)
+	checkEntries(t, list, game.LeaderboardQuery{Difficulty: "hard", Limit: 10}, nil, 0)
+}
+
+// testRefreshRotation vérifie qu'un token de rafraîchissement ne sert qu'une fois,
+// y compris quand plusieurs requêtes le présentent en même temps
+func testRefreshRotation(t *testing.T, store Store) {
+	user := createUser(t, store, "alice")
+
+	_, tokens, err := models.CreateSession(store, user.ID, "storetest")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	_, rotated, err := models.RefreshSession(store, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshSession: %v", err)
+	}
+	if _, err := models.ValidateToken(store, rotated.AccessToken); err != nil {
+		t.Fatalf("ValidateToken(new access token): %v", err)
+	}
+	if _, err := models.ValidateToken(store, tokens.AccessToken); !errors.Is(err, models.ErrInvalidToken) {
+		t.Fatalf("ValidateToken(old access token) = %v, want %v", err, models.ErrInvalidToken)
+	}
+	if _, _, err := models.RefreshSession(store, tokens.RefreshToken); !errors.Is(err, models.ErrInvalidToken) {
+		t.Fatalf("RefreshSession(old refresh token) = %v, want %v", err, models.ErrInvalidToken)
+	}
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := models.RefreshSession(store, rotated.RefreshToken)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, models.ErrInvalidToken):
+			t.Errorf("concurrent RefreshSession: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d concurrent refreshes succeeded, want 1", succeeded)
+	}
+}
+
+// testDuplicateIDRetry vérifie que les stockages signalent les collisions
+// d'identifiant par utils.ErrDuplicateID, pour qu'un nouvel ID soit tiré
+func testDuplicateIDRetry(t *testing.T, store Store) {
+	ids := &sequenceIDs{fallback: utils.NewULIDGenerator()}
+	utils.SetIDGenerator(ids)
+	t.Cleanup(func() { utils.SetIDGenerator(utils.NewULIDGenerator()) })
+
+	ids.push("U1")
+	alice := createUser(t, store, "alice")
+	ids.push("U1", "U2")
+	bob := createUser(t, store, "bob")
+	if bob.ID != "U2" {
+		t.Errorf("user ID after a collision = %q, want U2", bob.ID)
+	}
+
+	ids.push("G1")
+	first, err := game.NewGameWithOptions(store, game.GameOptions{PlayerID: alice.ID})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+	ids.push("G1", "G2")
+	second, err := game.NewGameWithOptions(store, game.GameOptions{PlayerID: bob.ID})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions after a collision: %v", err)
+	}
+	if second.ID != "G2" {
+		t.Errorf("game ID after a collision = %q, want G2", second.ID)
+	}
+
+	ids.push("S1")
+	if _, err := game.AddToLeaderboard(store, game.NewLeaderboardEntry(first, alice.ID, alice.Name)); err != nil {
+		t.Fatalf("AddToLeaderboard: %v", err)
+	}
+	ids.push("S1", "S2")
+	entry, err := game.AddToLeaderboard(store, game.NewLeaderboardEntry(second, bob.ID, bob.Name))
+	if err != nil {
+		t.Fatalf("AddToLeaderboard after a collision: %v", err)
+	}
+	if entry.ID != "S2" {
+		t.Errorf("leaderboard entry ID after a collision = %q, want S2", entry.ID)
+	}
+
+	// Une partie déjà soumise n'est pas une collision d'identifiant
+	ids.push("S3")
+	_, err = game.AddToLeaderboard(store, game.NewLeaderboardEntry(first, alice.ID, alice.Name))
+	if !errors.Is(err, game.ErrScoreAlreadySubmitted) {
+		t.Errorf("AddToLeaderboard(same game) = %v, want %v", err, game.ErrScoreAlreadySubmitted)
+	}
+}
+
+// testScoreSubmission vérifie qu'un score et les statistiques de son joueur sont
+// enregistrés ensemble, ou pas du tout
+func testScoreSubmission(t *testing.T, store Store) {
+	alice := createUser(t, store, "alice")
+	won := &game.Game{ID: "G1", PlayerID: alice.ID, Word: "PIXEL", Status: "won", Score: 320, Remaining: 5,
+		Difficulty: "medium", Language: "en"}
+
+	if _, err := game.SubmitScore(store, won, alice.ID, alice.Name); err != nil {
+		t.Fatalf("SubmitScore: %v", err)
+	}
+	checkStats(t, store, alice.ID, 1, 1, 320)
+
+	// Une seconde soumission est refusée sans toucher aux statistiques
+	if _, err := game.SubmitScore(store, won, alice.ID, alice.Name); !errors.Is(err, game.ErrScoreAlreadySubmitted) {
+		t.Fatalf("SubmitScore(same game) = %v, want %v", err, game.ErrScoreAlreadySubmitted)
+	}
+	checkStats(t, store, alice.ID, 1, 1, 320)
+
+	lost := &game.Game{ID: "G2", PlayerID: alice.ID, Word: "SPRITE", Status: "lost", Score: 40,
+		Difficulty: "medium", Language: "en"}
+	if _, err := game.SubmitScore(store, lost, alice.ID, alice.Name); err != nil {
+		t.Fatalf("SubmitScore(lost game): %v", err)
+	}
+	checkStats(t, store, alice.ID, 2, 1, 320)
+
+	// Sans joueur, le score n'est pas enregistré
+	orphan := &game.Game{ID: "G3", PlayerID: "missing", Word: "PIXEL", Status: "won", Score: 500,
+		Difficulty: "medium", Language: "en"}
+	if _, err := game.SubmitScore(store, orphan, "missing", "ghost"); !errors.Is(err, models.ErrUserNotFound) {
+		t.Fatalf("SubmitScore(unknown player) = %v, want %v", err, models.ErrUserNotFound)
+	}
+	if _, total, err := store.ListLeaderboardEntries(game.LeaderboardQuery{Limit: 10}); err != nil || total != 2 {
+		t.Fatalf("ListLeaderboardEntries = %d entries (%v), want 2", total, err)
+	}
+}
+
+// testGameVersions vérifie qu'un enregistrement fondé sur un état périmé est
+// refusé, comme celui d'un autre serveur qui n'a pas vu la dernière proposition
+func testGameVersions(t *testing.T, store Store) {
+	g, err := game.NewGameWithOptions(store, game.GameOptions{
+		Difficulty: "hard",
+		Word:       &game.WordSelection{Word: "PACK MY BOX WITH FIVE DOZEN LIQUOR JUGS", Hint: "Pangram"},
+	})
+	if err != nil {
+		t.Fatalf("NewGameWithOptions: %v", err)
+	}
+
+	first, err := store.GetGame(g.ID)
+	if err != nil {
+		t.Fatalf("GetGame: %v", err)
+	}
+	stale, err := store.GetGame(g.ID)
+	if err != nil {
+		t.Fatalf("GetGame: %v", err)
+	}
+	first.MakeGuess("A")
+	if err := store.SaveGame(first); err != nil {
+		t.Fatalf("SaveGame: %v", err)
+	}
+	stale.MakeGuess("B")
+	if err := store.SaveGame(stale); !errors.Is(err, game.ErrGameConflict) {
+		t.Fatalf("SaveGame(stale game) = %v, want %v", err, game.ErrGameConflict)
+	}
+
+	// Des serveurs sans verrou commun relisent la partie jusqu'à réussir
+	var wg sync.WaitGroup
+	for letter := 'C'; letter <= 'Z'; letter++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				current, err := store.GetGame(g.ID)
+				if err != nil {
+					t.Errorf("GetGame: %v", err)
+					return
+				}
+				current.MakeGuess(string(letter))
+				err = store.SaveGame(current)
+				if errors.Is(err, game.ErrGameConflict) {
+					continue
+				}
+				if err != nil {
+					t.Errorf("SaveGame(%c): %v", letter, err)
+				}
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	final, err := store.GetGame(g.ID)
+	if err != nil {
+		t.Fatalf("GetGame: %v", err)
+	}
+	if len(final.Guesses) != 25 || final.Version != 25 {
+		t.Errorf("after concurrent saves: %d guesses at version %d, want 25 at version 25", len(final.Guesses), final.Version)
+	}
+
+	if err := store.DeleteGame(g.ID); err != nil {
+		t.Fatalf("DeleteGame: %v", err)
+	}
+	if err := store.SaveGame(final); !errors.Is(err, game.ErrGameNotFound) {
+		t.Errorf("SaveGame(deleted game) = %v, want %v", err, game.ErrGameNotFound)
+	}
+}
+
+// testMatchVersions vérifie qu'une course enregistrée à partir d'un état périmé
+// est refusée : des serveurs qui font rejoindre des joueurs en même temps ne
+// perdent aucun d'eux
+func testMatchVersions(t *testing.T, store Store) {
+	m, err := game.NewMatch(store, game.MatchOptions{HostID: "host", HostName: "host"})
+	if err != nil {
+		t.Fatalf("NewMatch: %v", err)
+	}
+
+	first, err := store.GetMatch(m.ID)
+	if err != nil {
+		t.Fatalf("GetMatch: %v", err)
+	}
+	stale, err := store.GetMatchByCode(m.Code)
+	if err != nil {
+		t.Fatalf("GetMatchByCode: %v", err)
+	}
+	first.Players = append(first.Players, game.MatchPlayer{PlayerID: "P1", PlayerName: "p1", JoinedAt: time.Now().UTC()})
+	if err := store.SaveMatch(first); err != nil {
+		t.Fatalf("SaveMatch: %v", err)
+	}
+	stale.Status = game.MatchCancelled
+	if err := store.SaveMatch(stale); !errors.Is(err, game.ErrMatchConflict) {
+		t.Fatalf("SaveMatch(stale match) = %v, want %v", err, game.ErrMatchConflict)
+	}
+
+	// Des serveurs sans verrou commun relisent la course jusqu'à réussir
+	var wg sync.WaitGroup
+	for i := 2; i < game.MaxMatchPlayers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("P%d", i)
+			for {
+				current, err := store.GetMatch(m.ID)
+				if err != nil {
+					t.Errorf("GetMatch: %v", err)
+					return
+				}
+				current.Players = append(current.Players, game.MatchPlayer{PlayerID: id, PlayerName: id, JoinedAt: time.Now().UTC()})
+				err = store.SaveMatch(current)
+				if errors.Is(err, game.ErrMatchConflict) {
+					continue
+				}
+				if err != nil {
+					t.Errorf("SaveMatch(%s): %v", id, err)
+				}
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	final, err := store.GetMatch(m.ID)
+	if err != nil {
+		t.Fatalf("GetMatch: %v", err)
+	}
+	if len(final.Players) != game.MaxMatchPlayers || final.Version != game.MaxMatchPlayers-1 || final.Status != game.MatchLobby {
+		t.Errorf("after concurrent joins: %d players at version %d (%s), want %d at version %d in the lobby",
+			len(final.Players), final.Version, final.Status, game.MaxMatchPlayers, game.MaxMatchPlayers-1)
+	}
+}
+
+// testDailyPlays vérifie qu'un défi est enregistré une fois par jour et par
+// langue, et qu'un joueur n'y a qu'une partie, même demandée plusieurs fois en même temps
+func testDailyPlays(t *testing.T, store Store) {
+	alice := createUser(t, store, "alice")
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var words []string
+	for i := range 3 {
+		challenge, err := game.GetDailyChallenge(store, game.DailyDay(start.AddDate(0, 0, i)), "en")
+		if err != nil {
+			t.Fatalf("GetDailyChallenge: %v", err)
+		}
+		words = append(words, challenge.Word)
+	}
+	challenge, err := store.GetDailyChallenge("2024-03-02", "en")
+	if err != nil || challenge.Word != words[1] {
+		t.Fatalf("GetDailyChallenge(2024-03-02) = %v, %v; want %s", challenge, err, words[1])
+	}
+	duplicate := *challenge
+	duplicate.Word = "OTHER"
+	if err := store.CreateDailyChallenge(&duplicate); !errors.Is(err, game.ErrDailyExists) {
+		t.Errorf("CreateDailyChallenge(same day) = %v, want %v", err, game.ErrDailyExists)
+	}
+	listed, err := store.ListDailyWords("en", "2024-03-01", "2024-03-03")
+	if err != nil || fmt.Sprint(listed) != fmt.Sprint(words[:2]) {
+		t.Errorf("ListDailyWords = %v, %v; want %v", listed, err, words[:2])
+	}
+
+	const requests = 8
+	var wg sync.WaitGroup
+	ids := make(chan string, requests)
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g, err := game.PlayDaily(store, store, challenge, alice.ID)
+			if err != nil {
+				t.Errorf("PlayDaily: %v", err)
+				return
+			}
+			ids <- g.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	play, err := store.GetDailyPlay(challenge.Day, challenge.Language, alice.ID)
+	if err != nil {
+		t.Fatalf("GetDailyPlay: %v", err)
+	}
+	for id := range ids {
+		if id != play.GameID {
+			t.Errorf("concurrent PlayDaily returned game %s, want %s", id, play.GameID)
+		}
+	}
+	err = store.AddDailyPlay(game.DailyPlay{Day: challenge.Day, Language: challenge.Language, PlayerID: alice.ID, GameID: "other"})
+	if !errors.Is(err, game.ErrDailyAlreadyPlayed) {
+		t.Errorf("AddDailyPlay(second game) = %v, want %v", err, game.ErrDailyAlreadyPlayed)
+	}
+}
+
+// testWordBatch vérifie qu'un lot de mots est enregistré en entier, ou pas du
+// tout si l'une de ses modifications échoue
+func testWordBatch(t *testing.T, store Store) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	word := func(id, text string) *game.WordEntry {
+		return &game.WordEntry{ID: id, Word: text, Hint: "hint", Difficulty: "easy", Language: "en",
+			Tags: []string{}, Enabled: true, FromFiles: true, CreatedAt: at, UpdatedAt: at}
+	}
+	audit := func(id string, w *game.WordEntry) game.WordAudit {
+		return game.WordAudit{ID: id, WordID: w.ID, Action: game.WordCreated, UserName: "system", After: w, At: at}
+	}
+
+	first, second := word("W1", "PIXEL"), word("W2", "SPRITE")
+	err := store.SaveWords([]game.WordChange{
+		{Word: first, Audit: audit("A1", first), New: true},
+		{Word: second, Audit: audit("A2", second), New: true},
+	})
+	if err != nil {
+		t.Fatalf("SaveWords: %v", err)
+	}
+
+	// Le second mot du lot existe déjà : la modification du premier est annulée
+	renamed, duplicate := word("W1", "ARCADE"), word("W3", "SPRITE")
+	err = store.SaveWords([]game.WordChange{
+		{Word: renamed, Audit: audit("A3", renamed)},
+		{Word: duplicate, Audit: audit("A4", duplicate), New: true},
+	})
+	if !errors.Is(err, game.ErrWordExists) {
+		t.Fatalf("SaveWords(duplicate word) = %v, want %v", err, game.ErrWordExists)
+	}
+
+	words, err := store.ListWords(game.WordFilter{})
+	if err != nil {
+		t.Fatalf("ListWords: %v", err)
+	}
+	if len(words) != 2 || words[0].Word != "PIXEL" || words[1].Word != "SPRITE" || !words[0].FromFiles {
+		t.Errorf("words after a failed batch = %+v, want PIXEL and SPRITE from files", words)
+	}
+	entries, err := store.ListWordAudit("", 10)
+	if err != nil {
+		t.Fatalf("ListWordAudit: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("audit entries after a failed batch = %d, want 2", len(entries))
+	}
+	if count, err := store.CountWordAudit(); err != nil || count != 2 {
+		t.Errorf("CountWordAudit after a failed batch = %d, %v; want 2", count, err)
+	}
+
+	// Un lot peut créer un mot sous le nom qu'un autre mot du lot abandonne
+	created := word("W3", "PIXEL")
+	err = store.SaveWords([]game.WordChange{
+		{Word: renamed, Audit: audit("A3", renamed)},
+		{Word: created, Audit: audit("A4", created), New: true},
+	})
+	if err != nil {
+		t.Fatalf("SaveWords(rename then create): %v", err)
+	}
+	if w, err := store.FindWord("en", game.WordKey("PIXEL")); err != nil || w.ID != "W3" {
+		t.Errorf("FindWord(PIXEL) = %v, %v; want W3", w, err)
+	}
+}
+
+// sequenceIDs retourne les identifiants empilés, puis ceux de fallback
+type sequenceIDs struct {
+	mu       sync.Mutex
+	ids      []string
+	fallback utils.IDGenerator
+}
+
+func (s *sequenceIDs) push(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = append(s.ids, ids...)
+}
+
+func (s *sequenceIDs) NewID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ids) == 0 {
+		return s.fallback.NewID()
+	}
+	id := s.ids[0]
+	s.ids = s.ids[1:]
+	return id
+}
+
+func createUser(t *testing.T, store Store, name string) *models.User {
+	t.Helper()
+	user, err := models.CreateUser(store, name, "password", name+"@example.com")
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", name, err)
+	}
+	return user
+}
+
+func checkStats(t *testing.T, store Store, userID string, played, won, highScore int) {
+	t.Helper()
+	user, err := store.GetUser(userID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.GamesPlayed != played || user.GamesWon != won || user.HighScore != highScore {
+		t.Errorf("stats = %d played, %d won, high score %d; want %d, %d, %d",
+			user.GamesPlayed, user.GamesWon, user.HighScore, played, won, highScore)
+	}
+}
+
+func checkPage(t *testing.T, store Store, query game.LeaderboardQuery, wantIDs []string, wantTotal int) {
+	t.Helper()
+	checkEntries(t, store.ListLeaderboardEntries, query, wantIDs, wantTotal)
+}
+
+func checkEntries(t *testing.T, list func(game.LeaderboardQuery) ([]game.LeaderboardEntry, int, error), query game.LeaderboardQuery, wantIDs []string, wantTotal int) {
+	t.Helper()
+	entries, total, err := list(query)
+	if err != nil {
+		t.Fatalf("listing entries (%+v): %v", query, err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	if total != wantTotal || len(ids) != len(wantIDs) {
+		t.Fatalf("entries (%+v) = %v (total %d), want %v (total %d)", query, ids, total, wantIDs, wantTotal)
+	}
+	for i := range ids {
+		if ids[i] != wantIDs[i] {
+			t.Fatalf("entries (%+v) = %v, want %v", query, ids, wantIDs)
+		}
+	}
+}
+
+func checkStanding(t *testing.T, store Store, playerID string, query game.LeaderboardQuery, wantID string, wantRank int) {
+	t.Helper()
+	best, rank, err := store.PlayerStanding(playerID, query)
+	if err != nil {
+		t.Fatalf("PlayerStanding(%s): %v", playerID, err)
+	}
+	if best.ID != wantID || rank != wantRank {
+		t.Errorf("PlayerStanding(%s, %+v) = %s at rank %d, want %s at rank %d", playerID, query, best.ID, rank, wantID, wantRank)
+	}
+}
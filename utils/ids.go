@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateID est renvoyée par les stockages quand un identifiant existe déjà
+var ErrDuplicateID = errors.New("duplicate id")
+
+// maxIDAttempts borne le nombre de tentatives en cas de collision d'identifiant
+const maxIDAttempts = 5
+
+// IDGenerator produit les identifiants des utilisateurs, parties et sessions
+type IDGenerator interface {
+	NewID() string
+}
+
+// Générateur utilisé par NewID, remplaçable au démarrage ou dans les tests
+var idGenerator IDGenerator = NewULIDGenerator()
+
+// SetIDGenerator remplace le générateur d'identifiants
+func SetIDGenerator(g IDGenerator) {
+	idGenerator = g
+}
+
+// NewID génère un identifiant avec le générateur configuré
+func NewID() string {
+	return idGenerator.NewID()
+}
+
+// RetryOnDuplicateID appelle insert jusqu'à ce qu'il ne renvoie plus ErrDuplicateID.
+// insert doit générer ses nouveaux identifiants à chaque appel.
+func RetryOnDuplicateID(insert func() error) error {
+	var err error
+	for range maxIDAttempts {
+		err = insert()
+		if !errors.Is(err, ErrDuplicateID) {
+			return err
+		}
+	}
+	return err
+}
+
+// Alphabet base32 de Crockford utilisé par les ULID (sans I, L, O, U)
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator produit des identifiants triables façon ULID : 26 caractères
+// encodant 48 bits d'horodatage en millisecondes suivis de 80 bits aléatoires.
+// Dans une même milliseconde la partie aléatoire est incrémentée, ce qui garde
+// les identifiants strictement croissants.
+type ULIDGenerator struct {
+	mu          sync.Mutex
+	lastMillis  uint64
+	lastEntropy [10]byte
+}
+
+// NewULIDGenerator crée un générateur d'identifiants triables
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{}
+}
+
+// NewID génère un nouvel identifiant
+func (g *ULIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	millis := uint64(time.Now().UnixMilli())
+	if millis > g.lastMillis {
+		g.lastMillis = millis
+		rand.Read(g.lastEntropy[:])
+	} else {
+		incrementBytes(g.lastEntropy[:])
+	}
+
+	var raw [16]byte
+	binary.BigEndian.PutUint64(raw[:8], g.lastMillis<<16)
+	copy(raw[6:], g.lastEntropy[:])
+	return encodeCrockford(raw)
+}
+
+// incrementBytes incrémente un entier big-endian (un débordement repart de zéro)
+func incrementBytes(b []byte) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return
+		}
+	}
+}
+
+// encodeCrockford encode 128 bits en 26 caractères base32 de Crockford
+func encodeCrockford(raw [16]byte) string {
+	hi := binary.BigEndian.Uint64(raw[:8])
+	lo := binary.BigEndian.Uint64(raw[8:])
+
+	// 26 caractères de 5 bits = 130 bits : les 2 bits de poids fort sont nuls
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+// GenerateToken génère un secret aléatoire de 256 bits encodé en base64 URL
+func GenerateToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// GenerateShortCode génère un code court lisible (3 chiffres + 3 lettres),
+// destiné à être partagé entre joueurs et non à protéger une ressource
+func GenerateShortCode() string {
+	const digits = "0123456789"
+	const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	code := make([]byte, 6)
+	for i := range code {
+		if i < 3 {
+			code[i] = digits[randomIndex(len(digits))]
+		} else {
+			code[i] = letters[randomIndex(len(letters))]
+		}
+	}
+	return string(code)
+}
+
+// randomIndex tire un index uniforme dans [0, n) avec crypto/rand
+func randomIndex(n int) int {
+	// Rejeter les octets qui introduiraient un biais
+	limit := 256 - 256%n
+	var b [1]byte
+	for {
+		rand.Read(b[:])
+		if int(b[0]) < limit {
+			return int(b[0]) % n
+		}
+	}
+}